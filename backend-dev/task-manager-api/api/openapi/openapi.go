@@ -0,0 +1,146 @@
+// Package openapi embeds the hand-maintained OpenAPI document of the public
+// HTTP API and checks it against the routes the server registers.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var source []byte
+
+// Spec is the parsed document together with its JSON form, which is what
+// /openapi.json serves.
+type Spec struct {
+	Doc  *openapi3.T
+	JSON []byte
+}
+
+// Load parses and validates the embedded document.
+func Load() (*Spec, error) {
+	var raw any
+	if err := yaml.Unmarshal(source, &raw); err != nil {
+		return nil, fmt.Errorf("parse openapi.yaml: %w", err)
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi.yaml as JSON: %w", err)
+	}
+
+	// The validator predates OpenAPI 3.1, so it reads a copy with 3.0 style
+	// nullable schemas.
+	downgradeNullable(raw)
+	compat, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encode openapi.yaml as JSON: %w", err)
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(compat)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi.yaml: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi.yaml: %w", err)
+	}
+
+	return &Spec{Doc: doc, JSON: data}, nil
+}
+
+// CheckRoutes reports every registered route the document doesn't describe
+// and every operation without a route, so the two can't drift apart.
+// Operations marked x-optional may be switched off by configuration and
+// don't need a route. A path item's x-route names the router pattern when
+// it differs from the documented path.
+func (s *Spec) CheckRoutes(routes gin.RoutesInfo) error {
+	registered := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		registered[r.Method+" "+r.Path] = true
+	}
+
+	documented := make(map[string]bool)
+	var problems []string
+	for path, item := range s.Doc.Paths.Map() {
+		pattern := routePattern(path)
+		if route, ok := item.Extensions["x-route"].(string); ok {
+			pattern = route
+		}
+
+		for method, op := range item.Operations() {
+			key := method + " " + pattern
+			documented[key] = true
+			if !registered[key] && op.Extensions["x-optional"] != true {
+				problems = append(problems, fmt.Sprintf("%s %s is documented but has no route", method, path))
+			}
+		}
+	}
+
+	for _, r := range routes {
+		if r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			continue
+		}
+		if !documented[r.Method+" "+r.Path] {
+			problems = append(problems, fmt.Sprintf("%s %s is not documented", r.Method, r.Path))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.Sort(problems)
+	return errors.New("routes and openapi.yaml differ:\n  " + strings.Join(problems, "\n  "))
+}
+
+// downgradeNullable rewrites `type: [T, "null"]` into `type: T` plus
+// `nullable: true`, in place. Empty property and item schemas accept any
+// value in 3.1, null included, so they are marked nullable as well.
+func downgradeNullable(node any) {
+	switch node := node.(type) {
+	case map[string]any:
+		if types, ok := node["type"].([]any); ok && len(types) == 2 && slices.Contains(types, any("null")) {
+			for _, t := range types {
+				if t != "null" {
+					node["type"] = t
+				}
+			}
+			node["nullable"] = true
+		}
+		if properties, ok := node["properties"].(map[string]any); ok {
+			for _, property := range properties {
+				markAnyNullable(property)
+			}
+		}
+		markAnyNullable(node["items"])
+		for _, v := range node {
+			downgradeNullable(v)
+		}
+	case []any:
+		for _, v := range node {
+			downgradeNullable(v)
+		}
+	}
+}
+
+func markAnyNullable(schema any) {
+	if schema, ok := schema.(map[string]any); ok && len(schema) == 0 {
+		schema["nullable"] = true
+	}
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// routePattern turns /tasks/{id} into gin's /tasks/:id.
+func routePattern(path string) string {
+	return pathParam.ReplaceAllString(path, ":$1")
+}
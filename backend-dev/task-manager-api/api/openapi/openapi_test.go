@@ -0,0 +1,167 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/api/openapi"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/server"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newServer builds the API the way cmd/api does, on memory repositories,
+// with env set on top of the defaults.
+func newServer(t *testing.T, log zerolog.Logger, env map[string]string) *server.Server {
+	t.Helper()
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DATABASE_DRIVER", "memory")
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	srv, err := server.New(cfg, server.MemoryRepositories(), server.Options{Log: log})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+	return srv
+}
+
+func TestCheckRoutes(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	for _, playground := range []string{"true", "false"} {
+		t.Run("playground="+playground, func(t *testing.T) {
+			srv := newServer(t, zerolog.Nop(), map[string]string{"GRAPHQL_PLAYGROUND": playground})
+			if err := spec.CheckRoutes(srv.Router.Routes()); err != nil {
+				t.Fatalf("CheckRoutes: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckRoutesReportsDrift(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	routes := newServer(t, zerolog.Nop(), nil).Router.Routes()
+
+	undocumented := append(routes[:len(routes):len(routes)], gin.RouteInfo{Method: http.MethodGet, Path: "/api/v1/undocumented"})
+	err = spec.CheckRoutes(undocumented)
+	if err == nil || !strings.Contains(err.Error(), "GET /api/v1/undocumented is not documented") {
+		t.Errorf("CheckRoutes with an extra route = %v, want it reported", err)
+	}
+
+	var missing gin.RoutesInfo
+	for _, r := range routes {
+		if r.Method+" "+r.Path != "GET /api/v1/tasks" {
+			missing = append(missing, r)
+		}
+	}
+	err = spec.CheckRoutes(missing)
+	if err == nil || !strings.Contains(err.Error(), "GET /api/v1/tasks is documented but has no route") {
+		t.Errorf("CheckRoutes without a route = %v, want it reported", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// The served document keeps the 3.1 schemas the validator doesn't read.
+	var served struct {
+		OpenAPI string `json:"openapi"`
+	}
+	if err := json.Unmarshal(spec.JSON, &served); err != nil {
+		t.Fatalf("served document is not JSON: %v", err)
+	}
+	if !strings.HasPrefix(served.OpenAPI, "3.1") {
+		t.Errorf("served document is OpenAPI %q, want 3.1", served.OpenAPI)
+	}
+}
+
+// TestTrafficMatchesDocument runs requests through the router with request
+// and response validation on, and fails on every response the validator
+// logs as not matching the document.
+func TestTrafficMatchesDocument(t *testing.T) {
+	var logs bytes.Buffer
+	srv := newServer(t, zerolog.New(&logs), map[string]string{
+		"OPENAPI_VALIDATE_REQUESTS":  "true",
+		"OPENAPI_VALIDATE_RESPONSES": "true",
+	})
+
+	do := func(method, path, contentType, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		srv.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/v1/tasks", "application/json", `{"title":"Write tests","due_date":"2030-01-01T00:00:00Z"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create task: status %d: %s", w.Code, w.Body)
+	}
+	var task struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &task); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	requests := []struct {
+		method, path, contentType, body string
+		status                          int
+	}{
+		{http.MethodGet, "/api/v1/tasks", "", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/tasks?sort=rank&limit=1", "", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/tasks/" + task.ID, "", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/tasks/00000000-0000-0000-0000-000000000000", "", "", http.StatusNotFound},
+		{http.MethodPut, "/api/v1/tasks/" + task.ID, "application/json", `{"title":"Write more tests","status":"IN_PROGRESS"}`, http.StatusOK},
+		{http.MethodPatch, "/api/v1/tasks/" + task.ID, "application/json-patch+json", `[{"op":"test","path":"/title","value":"Write more tests"},{"op":"replace","path":"/due_date","value":null}]`, http.StatusOK},
+		{http.MethodPatch, "/api/v1/tasks/" + task.ID, "application/merge-patch+json", `{"description":"Cover the router"}`, http.StatusOK},
+		{http.MethodPost, "/api/v1/tasks:batch", "application/json", `{"operations":[{"op":"create","data":{"title":"Batch"}}]}`, http.StatusOK},
+		{http.MethodPost, "/graphql", "application/json", `{"query":"{ tasks { edges { node { id title } } } }"}`, http.StatusOK},
+		{http.MethodPost, "/graphql", "application/json", `{"query":"mutation { createTask(input: {title: \"\"}) { id } }"}`, http.StatusOK},
+		{http.MethodPost, "/api/v1/tasks/" + task.ID + "/assignees", "application/json", `{"user":"bob"}`, http.StatusOK},
+		{http.MethodGet, "/api/v1/tasks/" + task.ID + "/history", "", "", http.StatusOK},
+		{http.MethodDelete, "/api/v1/tasks/" + task.ID, "", "", http.StatusNoContent},
+		// Rejected by the request validator before reaching a handler.
+		{http.MethodPost, "/api/v1/tasks", "application/json", `{"title":"Bad","priority":"P9"}`, http.StatusBadRequest},
+	}
+	for _, r := range requests {
+		w := do(r.method, r.path, r.contentType, r.body)
+		if w.Code != r.status {
+			t.Errorf("%s %s: status %d, want %d: %s", r.method, r.path, w.Code, r.status, w.Body)
+		}
+	}
+
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if strings.Contains(line, "does not match the OpenAPI document") {
+			t.Errorf("response does not match the document: %s", line)
+		}
+	}
+}
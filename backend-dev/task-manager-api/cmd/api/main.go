@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,97 +13,247 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
-	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/handlers"
-	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/middlewares"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/grpcserver"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/health"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/metrics"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/cache"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/memory"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/postgres"
-	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/server"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/tracing"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/logger"
+	"github.com/rs/zerolog"
 )
 
 func main() {
-	// Initialize logger
-	log := logger.New()
+	// Console logger until the configured one is ready
+	bootLog := logger.Default()
 
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to load configuration")
+		bootLog.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 
+	// Initialize logger
+	log, logFile, err := logger.Open(cfg.Log.LoggerOptions())
+	if err != nil {
+		bootLog.Fatal().Err(err).Msg("Failed to open log file")
+	}
+	if logFile != nil {
+		defer logFile.Close()
+	}
+
+	// The level is global so a reload reaches every logger
+	zerolog.SetGlobalLevel(cfg.Log.ZerologLevel())
+
+	watcher := config.NewWatcher(cfg)
+	watcher.OnReload(func(cfg *config.Config) {
+		zerolog.SetGlobalLevel(cfg.Log.ZerologLevel())
+	})
+
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	go watcher.Watch(reloadCtx, log)
+
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Connect to database
-	db, err := postgres.NewConnection(cfg.DatabaseURL)
+	// Initialize tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to database")
+		log.Fatal().Err(err).Msg("Failed to initialize tracing")
 	}
-	defer db.Close()
 
 	// Initialize repository
-	taskRepo := postgres.NewTaskRepository(db)
-
-	// Initialize service
-	taskService := service.NewTaskService(taskRepo)
-
-	// Initialize handlers
-	taskHandler := handler.NewTaskHandler(taskService)
-
-	// Initialize router
-	router := gin.New()
-	router.Use(gin.Recovery())
-	router.Use(middleware.Logger(log))
-
-	// Register routes
-	v1 := router.Group("/api/v1")
-	{
-		tasks := v1.Group("/tasks")
-		{
-			tasks.GET("", taskHandler.ListTasks)
-			tasks.POST("", taskHandler.CreateTask)
-			tasks.GET("/:id", taskHandler.GetTask)
-			tasks.PUT("/:id", taskHandler.UpdateTask)
-			tasks.DELETE("/:id", taskHandler.DeleteTask)
+	var (
+		db    *sql.DB
+		repos server.Repositories
+	)
+	if cfg.Database.Driver == "memory" {
+		log.Warn().Msg("Keeping data in memory; it is lost on restart")
+		repos = server.MemoryRepositories()
+	} else {
+		db, err = postgres.NewConnection(cfg.Database)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to database")
+		}
+		defer db.Close()
+
+		repos = server.Repositories{
+			Tasks:         postgres.NewTaskRepository(db),
+			CalendarFeeds: postgres.NewCalendarFeedRepository(db),
+			Views:         postgres.NewViewRepository(db),
+			Boards:        postgres.NewBoardRepository(db),
+			Projects:      postgres.NewProjectRepository(db),
+			APITokens:     postgres.NewAPITokenRepository(db),
+			Idempotency:   postgres.NewIdempotencyRepository(db),
+			RateLimits:    memory.NewRateLimitStore(),
 		}
 	}
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "UP"})
+	pruneCtx, stopPrune := context.WithCancel(log.WithContext(context.Background()))
+	defer stopPrune()
+	if cfg.RateLimit.Store == "postgres" {
+		store := postgres.NewRateLimitStore(db)
+		go store.KeepPruned(pruneCtx, 5*time.Minute)
+		repos.RateLimits = store
+	}
+
+	// Initialize metrics
+	appMetrics := metrics.New(db)
+
+	// Cache task lookups
+	var taskCache cache.Backend
+	switch cfg.Cache.Backend {
+	case "memory":
+		taskCache = cache.NewLRU(cfg.Cache.Size)
+	case "redis":
+		dialCtx, cancelDial := context.WithTimeout(context.Background(), 5*time.Second)
+		redisClient, err := cache.DialRedis(dialCtx, cfg.Cache.RedisURL)
+		cancelDial()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to the Redis cache")
+		}
+		defer redisClient.Close()
+		taskCache = cache.NewRedis(redisClient, cfg.Cache.KeyPrefix)
+	}
+	if taskCache != nil {
+		repos.Tasks = cache.NewTaskRepository(repos.Tasks, taskCache, cfg.Cache.TTL, cache.WithObserver(func(result cache.Result) {
+			appMetrics.CacheLookups.WithLabelValues(string(result)).Inc()
+		}))
+	}
+
+	// Build the services and the router; this refuses to start with routes
+	// the OpenAPI document or the access policy doesn't cover
+	healthRegistry := health.NewRegistry(cfg.Health.CacheTTL)
+	api, err := server.New(cfg, repos, server.Options{
+		Log: log,
+		RateLimit: func() config.RateLimitConfig {
+			return watcher.Current().RateLimit
+		},
+		Metrics: appMetrics,
+		Health:  healthRegistry,
 	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to build the API")
+	}
+	taskService := api.Tasks
+
+	// Check saved views against the task fields they refer to
+	revalidateCtx, cancelRevalidate := context.WithTimeout(log.WithContext(context.Background()), 30*time.Second)
+	broken, err := api.Views.RevalidateViews(revalidateCtx)
+	cancelRevalidate()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to revalidate saved views")
+	} else if broken > 0 {
+		log.Warn().Int("views", broken).Msg("Some saved views need updating")
+	}
+
+	statsWorker := health.NewHeartbeat()
+	statsCtx, stopStats := context.WithCancel(context.Background())
+	defer stopStats()
+	go appMetrics.RefreshTaskStats(statsCtx, cfg.Metrics.RefreshInterval, func(ctx context.Context) (*domain.TaskStats, error) {
+		statsWorker.Beat()
+		return taskService.TaskStats(ctx)
+	}, log)
+
+	rankCtx, stopRank := context.WithCancel(log.WithContext(context.Background()))
+	defer stopRank()
+	go taskService.KeepRanksBalanced(rankCtx, cfg.Rank.CheckInterval, cfg.Rank.MaxLength)
+
+	// Initialize health checks
+	healthRegistry.AddLiveness("stats_worker", cfg.Health.CheckTimeout, statsWorker.Check(3*cfg.Metrics.RefreshInterval))
+	if db != nil {
+		healthRegistry.AddReadiness("database", cfg.Health.CheckTimeout, postgres.Ping(db))
+		healthRegistry.AddReadiness("schema", cfg.Health.CheckTimeout, postgres.CheckSchema(db))
+	}
 
 	// Create HTTP server
 	srv := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:      api.Router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	// Admin server, kept off the public port
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", appMetrics.Handler())
+	adminMux.Handle("/log/level", logger.LevelHandler(log))
+
+	adminSrv := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Server.AdminPort),
+		Handler:      adminMux,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
-	// Start server in a goroutine
+	// gRPC server, sharing the task service with the REST API
+	grpcSrv := grpcserver.New(taskService, api.Authenticator, log)
+
+	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Server.GRPCPort))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to listen for gRPC")
+	}
+
+	// Start servers in goroutines
 	go func() {
-		log.Info().Msgf("Starting server on port %d", cfg.Port)
+		log.Info().Msgf("Starting server on port %d", cfg.Server.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatal().Err(err).Msg("Server failed")
 		}
 	}()
 
+	go func() {
+		log.Info().Msgf("Starting admin server on port %d", cfg.Server.AdminPort)
+		if err := adminSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal().Err(err).Msg("Admin server failed")
+		}
+	}()
+
+	go func() {
+		log.Info().Msgf("Starting gRPC server on port %d", cfg.Server.GRPCPort)
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			log.Fatal().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Info().Msg("Shutting down server...")
 
+	// Fail readiness first and give load balancers time to drain us
+	healthRegistry.Shutdown()
+	time.Sleep(cfg.Health.DrainDelay)
+
 	// Create a deadline for server shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Server forced to shutdown")
 	}
 
+	grpcSrv.Shutdown(ctx)
+
+	stopStats()
+	stopRank()
+	stopPrune()
+	if err := adminSrv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Admin server forced to shutdown")
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to flush traces")
+	}
+
 	log.Info().Msg("Server exiting")
 }
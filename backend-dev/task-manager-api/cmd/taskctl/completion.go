@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout keeps a slow or unreachable server from hanging the
+// shell while completing.
+const completionTimeout = 3 * time.Second
+
+// completeTaskIDs completes task arguments with short IDs, showing titles as
+// descriptions. IDs already on the command line are left out, and so are
+// tasks keep rejects when it is not nil.
+func (o *options) completeTaskIDs(keep func(*client.Task) bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if cmd.Args != nil && cmd.Args(cmd, append(args, "")) != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		s, err := o.connect()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), completionTimeout)
+		defer cancel()
+
+		var completions []cobra.Completion
+		it := s.client.Tasks(ctx, listPageSize)
+		for it.Next() {
+			t := it.Task()
+			id := shortID(t)
+			if !strings.HasPrefix(id, strings.ToLower(toComplete)) || slices.Contains(args, id) {
+				continue
+			}
+			if keep != nil && !keep(t) {
+				continue
+			}
+			completions = append(completions, cobra.CompletionWithDesc(id, t.Title))
+		}
+		if it.Err() != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func (o *options) completeProfiles(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg, err := loadConfig(o.path())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return cfg.names(), cobra.ShellCompDirectiveNoFileComp
+}
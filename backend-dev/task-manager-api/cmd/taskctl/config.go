@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the config file, e.g.
+//
+//	current: local
+//	profiles:
+//	  local:
+//	    server: http://localhost:8080
+//	  prod:
+//	    server: https://tasks.example.com
+//	    token: eyJhbGciOi...
+//	    output: json
+type fileConfig struct {
+	Current  string              `yaml:"current,omitempty"`
+	Profiles map[string]*profile `yaml:"profiles,omitempty"`
+}
+
+type profile struct {
+	Server string `yaml:"server"`
+	Token  string `yaml:"token,omitempty"`
+	APIKey string `yaml:"api_key,omitempty"`
+	Output string `yaml:"output,omitempty"`
+}
+
+// path is the config file in use: --config, then $TASKCTL_CONFIG, then
+// taskctl/config.yaml in the user's config directory.
+func (o *options) path() string {
+	if p := firstNonEmpty(o.configPath, os.Getenv("TASKCTL_CONFIG")); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "taskctl.yaml"
+	}
+	return filepath.Join(dir, "taskctl", "config.yaml")
+}
+
+func displayConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "taskctl.yaml"
+	}
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(dir, home) {
+		dir = "~" + strings.TrimPrefix(dir, home)
+	}
+	return filepath.Join(dir, "taskctl", "config.yaml")
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig(path string) (*fileConfig, error) {
+	cfg := &fileConfig{Profiles: map[string]*profile{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*profile{}
+	}
+	return cfg, nil
+}
+
+// save writes the config file readable only by the user, since profiles
+// hold credentials.
+func (c *fileConfig) save(path string) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+func (c *fileConfig) names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func newProfileCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named server profiles",
+		Long: `Profiles name the servers taskctl talks to and how to authenticate with
+them. They are kept in the config file; the current profile is used unless
+--profile or $TASKCTL_PROFILE picks another one.`,
+	}
+	cmd.AddCommand(
+		newProfileListCommand(opts),
+		newProfileSetCommand(opts),
+		newProfileUseCommand(opts),
+		newProfileRmCommand(opts),
+	)
+	return cmd
+}
+
+func newProfileListCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List profiles, marking the current one",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := loadConfig(opts.path())
+			if err != nil {
+				return err
+			}
+			if len(cfg.Profiles) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No profiles in %s; using %s\n", opts.path(), defaultServer)
+				return nil
+			}
+			for _, name := range cfg.names() {
+				marker := " "
+				if name == cfg.Current {
+					marker = "*"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s %-16s %s\n", marker, name, cfg.Profiles[name].Server)
+			}
+			return nil
+		},
+	}
+}
+
+func newProfileSetCommand(opts *options) *cobra.Command {
+	var p profile
+	cmd := &cobra.Command{
+		Use:   "set NAME",
+		Short: "Create or update a profile",
+		Example: `  taskctl profile set local --server http://localhost:8080
+  taskctl profile set prod --server https://tasks.example.com --api-key "$TASKS_API_KEY"`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(opts.path())
+			if err != nil {
+				return err
+			}
+
+			name := args[0]
+			existing, ok := cfg.Profiles[name]
+			if !ok {
+				if p.Server == "" {
+					return errors.New("--server is required for a new profile")
+				}
+				existing = &profile{}
+				cfg.Profiles[name] = existing
+			}
+
+			flags := cmd.Flags()
+			if flags.Changed("server") {
+				existing.Server = p.Server
+			}
+			if flags.Changed("token") {
+				existing.Token = p.Token
+			}
+			if flags.Changed("api-key") {
+				existing.APIKey = p.APIKey
+			}
+			if flags.Changed("output") {
+				if _, err := newPrinter(p.Output, nil); err != nil {
+					return err
+				}
+				existing.Output = p.Output
+			}
+			if cfg.Current == "" {
+				cfg.Current = name
+			}
+			return cfg.save(opts.path())
+		},
+	}
+
+	// These shadow the global --server, --token and --output, which mean
+	// "for this call only".
+	cmd.Flags().StringVar(&p.Server, "server", "", "server URL")
+	cmd.Flags().StringVar(&p.Token, "token", "", "bearer token")
+	cmd.Flags().StringVar(&p.APIKey, "api-key", "", "API key, used when there is no token")
+	cmd.Flags().StringVarP(&p.Output, "output", "o", "", "default output format")
+	_ = cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func newProfileUseCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "use NAME",
+		Short:             "Make a profile the current one",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeProfiles,
+		RunE: func(_ *cobra.Command, args []string) error {
+			cfg, err := loadConfig(opts.path())
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("no profile named %q", args[0])
+			}
+			cfg.Current = args[0]
+			return cfg.save(opts.path())
+		},
+	}
+}
+
+func newProfileRmCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "rm NAME",
+		Short:             "Remove a profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeProfiles,
+		RunE: func(_ *cobra.Command, args []string) error {
+			cfg, err := loadConfig(opts.path())
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("no profile named %q", args[0])
+			}
+			delete(cfg.Profiles, args[0])
+			if cfg.Current == args[0] {
+				cfg.Current = ""
+			}
+			return cfg.save(opts.path())
+		},
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// editableTask is the part of a task edit lets the user change.
+type editableTask struct {
+	Title       string     `yaml:"title"`
+	Status      string     `yaml:"status"`
+	DueDate     *time.Time `yaml:"due_date"`
+	Description string     `yaml:"description"`
+}
+
+const editHeader = `# Editing task %s.
+# Lines starting with '#' are ignored. Status is one of TODO, IN_PROGRESS
+# or DONE; due_date is a date or an RFC 3339 timestamp. Close the editor
+# without saving changes to cancel.
+`
+
+func newEditCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit ID",
+		Short: "Edit a task in $EDITOR",
+		Long: `Edit opens a YAML rendering of the task in $VISUAL or $EDITOR (vi when
+neither is set) and saves the fields that changed once the editor exits.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs(nil),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := opts.connect()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			id, err := resolveID(ctx, s.client, args[0])
+			if err != nil {
+				return err
+			}
+			task, err := s.client.GetTask(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			// Time spent in the editor doesn't count against --timeout.
+			cancel()
+			before := editableTask{
+				Title:       task.Title,
+				Status:      string(task.Status),
+				DueDate:     task.DueDate,
+				Description: task.Description,
+			}
+			after, err := editInEditor(task, before)
+			if err != nil {
+				return err
+			}
+			input, err := diff(before, after)
+			if err != nil {
+				return err
+			}
+			if input == (client.UpdateTaskInput{}) {
+				fmt.Fprintln(cmd.ErrOrStderr(), "No changes")
+				return nil
+			}
+
+			ctx, cancel = opts.context(cmd)
+			defer cancel()
+			task, err = s.client.UpdateTask(ctx, id, input)
+			if err != nil {
+				return err
+			}
+			return s.printer.Task(task)
+		},
+	}
+}
+
+// editInEditor lets the user edit doc in a temporary file. When the result
+// doesn't parse the file is kept and its path is part of the error, so the
+// edits aren't lost.
+func editInEditor(task *client.Task, doc editableTask) (editableTask, error) {
+	var original bytes.Buffer
+	fmt.Fprintf(&original, editHeader, task.ID)
+	enc := yaml.NewEncoder(&original)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return doc, err
+	}
+	if err := enc.Close(); err != nil {
+		return doc, err
+	}
+
+	f, err := os.CreateTemp("", "taskctl-"+shortID(task)+"-*.yaml")
+	if err != nil {
+		return doc, err
+	}
+	path := f.Name()
+	_, err = f.Write(original.Bytes())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return doc, err
+	}
+
+	if err := runEditor(path); err != nil {
+		os.Remove(path)
+		return doc, err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return doc, err
+	}
+	if bytes.Equal(edited, original.Bytes()) {
+		os.Remove(path)
+		return doc, nil
+	}
+
+	var result editableTask
+	if err := yaml.Unmarshal(edited, &result); err != nil {
+		return doc, fmt.Errorf("%w; your edits are in %s", err, path)
+	}
+	os.Remove(path)
+	return result, nil
+}
+
+// runEditor opens path in the user's editor, which may carry arguments,
+// such as "code --wait".
+func runEditor(path string) error {
+	editor := firstNonEmpty(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi")
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s: %w", editor, err)
+	}
+	return nil
+}
+
+// diff turns an edit into an update of just the fields that changed.
+func diff(before, after editableTask) (client.UpdateTaskInput, error) {
+	var input client.UpdateTaskInput
+	if after.Title != before.Title {
+		input.Title = &after.Title
+	}
+	if after.Description != before.Description {
+		input.Description = &after.Description
+	}
+	if after.Status != before.Status {
+		status, err := parseStatus(after.Status)
+		if err != nil {
+			return input, err
+		}
+		input.Status = &status
+	}
+	switch {
+	case after.DueDate == nil && before.DueDate != nil:
+		return input, errors.New("the API can't remove a due date; set a new one instead")
+	case after.DueDate != nil && (before.DueDate == nil || !after.DueDate.Equal(*before.DueDate)):
+		input.DueDate = after.DueDate
+	}
+	return input, nil
+}
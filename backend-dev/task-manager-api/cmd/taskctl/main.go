@@ -0,0 +1,159 @@
+// Command taskctl manages tasks on a task manager server from the shell.
+//
+//	taskctl list --status TODO
+//	taskctl create "Write docs" --due 2024-06-01
+//	taskctl edit 3b02ff19
+//	taskctl done 3b02ff19 7c1e0a42
+//
+// Servers are picked from named profiles in the config file, see
+// `taskctl profile --help`. Run `taskctl completion --help` to set up shell
+// completion.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// options are the global flags, shared by every command.
+type options struct {
+	configPath string
+	profile    string
+	server     string
+	token      string
+	output     string
+	timeout    time.Duration
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "taskctl:", describe(err))
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:           "taskctl",
+		Short:         "Manage tasks on a task manager server",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configPath, "config", "", "config file (default $TASKCTL_CONFIG or "+displayConfigPath()+")")
+	flags.StringVarP(&opts.profile, "profile", "p", "", "server profile to use (default $TASKCTL_PROFILE or the current profile)")
+	flags.StringVar(&opts.server, "server", "", "server URL, overriding the profile (default $TASKCTL_SERVER)")
+	flags.StringVar(&opts.token, "token", "", "bearer token, overriding the profile (default $TASKCTL_TOKEN)")
+	flags.StringVarP(&opts.output, "output", "o", "", "output format: table, json or yaml (default from the profile, else table)")
+	flags.DurationVar(&opts.timeout, "timeout", 30*time.Second, "time limit for each command")
+
+	_ = root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = root.RegisterFlagCompletionFunc("profile", opts.completeProfiles)
+
+	root.AddCommand(
+		newListCommand(opts),
+		newShowCommand(opts),
+		newCreateCommand(opts),
+		newEditCommand(opts),
+		newDoneCommand(opts),
+		newRmCommand(opts),
+		newProfileCommand(opts),
+	)
+	return root
+}
+
+// session is what a command needs to talk to the server.
+type session struct {
+	client  *client.Client
+	printer printer
+}
+
+// connect resolves the profile and flags into a client and a printer.
+func (o *options) connect() (*session, error) {
+	cfg, err := loadConfig(o.path())
+	if err != nil {
+		return nil, err
+	}
+
+	name := firstNonEmpty(o.profile, os.Getenv("TASKCTL_PROFILE"), cfg.Current)
+	prof := &profile{}
+	if name != "" {
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			return nil, fmt.Errorf("no profile named %q in %s", name, o.path())
+		}
+		prof = p
+	}
+
+	server := firstNonEmpty(o.server, os.Getenv("TASKCTL_SERVER"), prof.Server, defaultServer)
+	token := firstNonEmpty(o.token, os.Getenv("TASKCTL_TOKEN"), prof.Token)
+
+	clientOpts := []client.Option{client.WithUserAgent("taskctl")}
+	switch {
+	case token != "":
+		clientOpts = append(clientOpts, client.WithAuth(client.BearerToken(token)))
+	case prof.APIKey != "":
+		clientOpts = append(clientOpts, client.WithAuth(client.APIKey(prof.APIKey)))
+	}
+	c, err := client.New(server, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newPrinter(firstNonEmpty(o.output, prof.Output, "table"), os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	return &session{client: c, printer: p}, nil
+}
+
+// context bounds a command by --timeout.
+func (o *options) context(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), o.timeout)
+}
+
+// describe turns API errors into messages that read well on a terminal.
+func describe(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timed out waiting for the server (see --timeout)"
+	case errors.Is(err, context.Canceled):
+		return "interrupted"
+	case errors.Is(err, client.ErrUnauthorized):
+		return "the server rejected the credentials; check the profile's token or api_key"
+	}
+
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && apiErr.Message != "" {
+		if apiErr.RequestID != "" {
+			return fmt.Sprintf("%s (request %s)", apiErr.Message, apiErr.RequestID)
+		}
+		return apiErr.Message
+	}
+	return err.Error()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
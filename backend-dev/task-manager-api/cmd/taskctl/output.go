@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+var outputFormats = []string{"table", "json", "yaml"}
+
+// printer writes tasks in one output format.
+type printer interface {
+	Task(task *client.Task) error
+	Tasks(tasks []*client.Task) error
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	switch format {
+	case "table":
+		return tablePrinter{w}, nil
+	case "json":
+		return jsonPrinter{w}, nil
+	case "yaml":
+		return yamlPrinter{w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, want one of %s", format, strings.Join(outputFormats, ", "))
+	}
+}
+
+const (
+	shortIDLen     = 8
+	maxTitleLength = 60
+)
+
+// tablePrinter is for people: short IDs, local times and truncated titles.
+type tablePrinter struct {
+	w io.Writer
+}
+
+func (p tablePrinter) Tasks(tasks []*client.Task) error {
+	if len(tasks) == 0 {
+		_, err := fmt.Fprintln(p.w, "No tasks")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tDUE\tTITLE")
+	for _, t := range tasks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", shortID(t), t.Status, formatDue(t.DueDate), truncate(t.Title, maxTitleLength))
+	}
+	return tw.Flush()
+}
+
+func (p tablePrinter) Task(t *client.Task) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%s\n", t.ID)
+	fmt.Fprintf(tw, "Title:\t%s\n", t.Title)
+	fmt.Fprintf(tw, "Status:\t%s\n", t.Status)
+	fmt.Fprintf(tw, "Due:\t%s\n", formatDue(t.DueDate))
+	fmt.Fprintf(tw, "Created:\t%s\n", formatTime(t.CreatedAt))
+	fmt.Fprintf(tw, "Updated:\t%s\n", formatTime(t.UpdatedAt))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if t.Description != "" {
+		_, err := fmt.Fprintf(p.w, "\n%s\n", strings.TrimRight(t.Description, "\n"))
+		return err
+	}
+	return nil
+}
+
+type jsonPrinter struct {
+	w io.Writer
+}
+
+func (p jsonPrinter) Task(t *client.Task) error {
+	return p.encode(t)
+}
+
+func (p jsonPrinter) Tasks(tasks []*client.Task) error {
+	if tasks == nil {
+		tasks = []*client.Task{}
+	}
+	return p.encode(tasks)
+}
+
+func (p jsonPrinter) encode(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+type yamlPrinter struct {
+	w io.Writer
+}
+
+func (p yamlPrinter) Task(t *client.Task) error {
+	return p.encode(toYAML(t))
+}
+
+func (p yamlPrinter) Tasks(tasks []*client.Task) error {
+	docs := make([]yamlTask, len(tasks))
+	for i, t := range tasks {
+		docs[i] = toYAML(t)
+	}
+	return p.encode(docs)
+}
+
+func (p yamlPrinter) encode(v any) error {
+	enc := yaml.NewEncoder(p.w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// yamlTask gives YAML output the same field names as the API's JSON.
+type yamlTask struct {
+	ID          string     `yaml:"id"`
+	Title       string     `yaml:"title"`
+	Description string     `yaml:"description"`
+	Status      string     `yaml:"status"`
+	DueDate     *time.Time `yaml:"due_date,omitempty"`
+	CreatedAt   time.Time  `yaml:"created_at"`
+	UpdatedAt   time.Time  `yaml:"updated_at"`
+}
+
+func toYAML(t *client.Task) yamlTask {
+	return yamlTask{
+		ID:          t.ID.String(),
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      string(t.Status),
+		DueDate:     t.DueDate,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// shortID is the prefix of the ID shown in tables; commands accept it in
+// place of the full ID.
+func shortID(t *client.Task) string {
+	return t.ID.String()[:shortIDLen]
+}
+
+func formatDue(due *time.Time) string {
+	if due == nil {
+		return "-"
+	}
+	return formatTime(*due)
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+var taskStatuses = []string{
+	string(client.TaskStatusTodo),
+	string(client.TaskStatusInProgress),
+	string(client.TaskStatusDone),
+}
+
+// listPageSize is the most tasks the API returns per page.
+const listPageSize = 100
+
+func newListCommand(opts *options) *cobra.Command {
+	var (
+		statuses []string
+		limit    int
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List tasks, newest first",
+		Example: `  taskctl list
+  taskctl list --status TODO,IN_PROGRESS --limit 20
+  taskctl list -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for i, s := range statuses {
+				status, err := parseStatus(s)
+				if err != nil {
+					return err
+				}
+				statuses[i] = string(status)
+			}
+
+			s, err := opts.connect()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			var tasks []*client.Task
+			it := s.client.Tasks(ctx, listPageSize)
+			for (limit <= 0 || len(tasks) < limit) && it.Next() {
+				if t := it.Task(); len(statuses) == 0 || slices.Contains(statuses, string(t.Status)) {
+					tasks = append(tasks, t)
+				}
+			}
+			if err := it.Err(); err != nil {
+				return err
+			}
+			return s.printer.Tasks(tasks)
+		},
+	}
+	cmd.Flags().StringSliceVarP(&statuses, "status", "s", nil, "only list tasks with these statuses")
+	cmd.Flags().IntVarP(&limit, "limit", "n", 0, "list at most this many tasks (0 lists all)")
+	_ = cmd.RegisterFlagCompletionFunc("status", cobra.FixedCompletions(taskStatuses, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func newShowCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "show ID",
+		Aliases:           []string{"get"},
+		Short:             "Show a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs(nil),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := opts.connect()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			id, err := resolveID(ctx, s.client, args[0])
+			if err != nil {
+				return err
+			}
+			task, err := s.client.GetTask(ctx, id)
+			if err != nil {
+				return err
+			}
+			return s.printer.Task(task)
+		},
+	}
+}
+
+func newCreateCommand(opts *options) *cobra.Command {
+	var (
+		description string
+		status      string
+		due         string
+	)
+	cmd := &cobra.Command{
+		Use:     "create TITLE",
+		Aliases: []string{"add"},
+		Short:   "Create a task",
+		Example: `  taskctl create "Write release notes" --due 2024-06-01
+  taskctl create "Fix login" --status IN_PROGRESS -d "Users get logged out after 5 minutes"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := client.CreateTaskInput{
+				Title:       args[0],
+				Description: description,
+			}
+			if status != "" {
+				s, err := parseStatus(status)
+				if err != nil {
+					return err
+				}
+				input.Status = s
+			}
+			if due != "" {
+				t, err := parseDue(due)
+				if err != nil {
+					return err
+				}
+				input.DueDate = &t
+			}
+
+			s, err := opts.connect()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			task, err := s.client.CreateTask(ctx, input)
+			if err != nil {
+				return err
+			}
+			return s.printer.Task(task)
+		},
+	}
+	cmd.Flags().StringVarP(&description, "description", "d", "", "task description")
+	cmd.Flags().StringVarP(&status, "status", "s", "", "initial status (default TODO)")
+	cmd.Flags().StringVar(&due, "due", "", "due date, as 2006-01-02 or RFC 3339")
+	_ = cmd.RegisterFlagCompletionFunc("status", cobra.FixedCompletions(taskStatuses, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func newDoneCommand(opts *options) *cobra.Command {
+	notDone := func(t *client.Task) bool { return t.Status != client.TaskStatusDone }
+	return &cobra.Command{
+		Use:               "done ID...",
+		Short:             "Mark tasks done",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs(notDone),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := opts.connect()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			ids, err := resolveIDs(ctx, s.client, args)
+			if err != nil {
+				return err
+			}
+			done := client.TaskStatusDone
+			tasks := make([]*client.Task, 0, len(ids))
+			for _, id := range ids {
+				task, err := s.client.UpdateTask(ctx, id, client.UpdateTaskInput{Status: &done})
+				if err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+				tasks = append(tasks, task)
+			}
+			return s.printer.Tasks(tasks)
+		},
+	}
+}
+
+func newRmCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "rm ID...",
+		Aliases:           []string{"delete"},
+		Short:             "Delete tasks",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs(nil),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := opts.connect()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := opts.context(cmd)
+			defer cancel()
+
+			ids, err := resolveIDs(ctx, s.client, args)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if err := s.client.DeleteTask(ctx, id); err != nil {
+					return fmt.Errorf("%s: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+// resolveIDs resolves every argument before anything is changed, so a typo
+// in the last one doesn't leave the command half done.
+func resolveIDs(ctx context.Context, c *client.Client, args []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(args))
+	for i, arg := range args {
+		id, err := resolveID(ctx, c, arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// resolveID accepts a full task ID or, like git, any unambiguous prefix of
+// one, such as the short IDs in table output.
+func resolveID(ctx context.Context, c *client.Client, arg string) (uuid.UUID, error) {
+	if id, err := uuid.Parse(arg); err == nil {
+		return id, nil
+	}
+
+	prefix := strings.ToLower(arg)
+	if len(prefix) < 4 {
+		return uuid.Nil, fmt.Errorf("%q is too short to identify a task, use at least 4 characters of its ID", arg)
+	}
+
+	var matches []uuid.UUID
+	it := c.Tasks(ctx, listPageSize)
+	for it.Next() {
+		if id := it.Task().ID; strings.HasPrefix(id.String(), prefix) {
+			matches = append(matches, id)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return uuid.Nil, err
+	}
+
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("no task ID starts with %q", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return uuid.Nil, fmt.Errorf("%q matches %d tasks, use more characters of the ID", arg, len(matches))
+	}
+}
+
+// parseStatus accepts a status in any case, e.g. in_progress.
+func parseStatus(s string) (client.TaskStatus, error) {
+	status := strings.ToUpper(s)
+	if !slices.Contains(taskStatuses, status) {
+		return "", fmt.Errorf("unknown status %q, want one of %s", s, strings.Join(taskStatuses, ", "))
+	}
+	return client.TaskStatus(status), nil
+}
+
+// parseDue reads a due date given as a day, taken as midnight local time, or
+// as an RFC 3339 timestamp.
+func parseDue(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("due date must look like 2006-01-02 or 2006-01-02T15:04:05Z07:00")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+)
+
+// requestTimeout bounds every API call so a hung server can't freeze the
+// board.
+const requestTimeout = 10 * time.Second
+
+// Messages delivered to Update by the commands below.
+type (
+	// tasksLoadedMsg carries all tasks as of requestedAt.
+	tasksLoadedMsg struct {
+		tasks       []*client.Task
+		requestedAt time.Time
+	}
+
+	taskSavedMsg struct {
+		task *client.Task
+	}
+
+	apiErrorMsg struct {
+		action string
+		err    error
+	}
+
+	tickMsg time.Time
+)
+
+func tick(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func loadTasks(c *client.Client) tea.Cmd {
+	return func() tea.Msg {
+		requestedAt := time.Now()
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		var tasks []*client.Task
+		it := c.Tasks(ctx, 100)
+		for it.Next() {
+			tasks = append(tasks, it.Task())
+		}
+		if err := it.Err(); err != nil {
+			return apiErrorMsg{action: "refresh", err: err}
+		}
+		return tasksLoadedMsg{tasks: tasks, requestedAt: requestedAt}
+	}
+}
+
+func createTask(c *client.Client, input client.CreateTaskInput) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		task, err := c.CreateTask(ctx, input)
+		if err != nil {
+			return apiErrorMsg{action: "create", err: err}
+		}
+		return taskSavedMsg{task: task}
+	}
+}
+
+func updateTask(c *client.Client, id uuid.UUID, input client.UpdateTaskInput) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		task, err := c.UpdateTask(ctx, id, input)
+		if err != nil {
+			return apiErrorMsg{action: "update", err: err}
+		}
+		return taskSavedMsg{task: task}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import "github.com/charmbracelet/bubbles/key"
+
+// boardKeys are the bindings while browsing the board.
+type boardKeys struct {
+	Left, Right, Up, Down key.Binding
+	MoveLeft, MoveRight   key.Binding
+	Edit, New             key.Binding
+	Filter, ClearFilter   key.Binding
+	Refresh, Help, Quit   key.Binding
+}
+
+var keys = boardKeys{
+	Left:        key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "column left")),
+	Right:       key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "column right")),
+	Up:          key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+	Down:        key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+	MoveLeft:    key.NewBinding(key.WithKeys("shift+left", "H", "<"), key.WithHelp("H/<", "move card left")),
+	MoveRight:   key.NewBinding(key.WithKeys("shift+right", "L", ">"), key.WithHelp("L/>", "move card right")),
+	Edit:        key.NewBinding(key.WithKeys("enter", "e"), key.WithHelp("enter/e", "edit")),
+	New:         key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new task")),
+	Filter:      key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+	ClearFilter: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear filter")),
+	Refresh:     key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+	Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "more keys")),
+	Quit:        key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+}
+
+func (k boardKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.MoveLeft, k.MoveRight, k.Edit, k.New, k.Filter, k.Help, k.Quit}
+}
+
+func (k boardKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Left, k.Right, k.Up, k.Down},
+		{k.MoveLeft, k.MoveRight, k.Edit, k.New},
+		{k.Filter, k.ClearFilter, k.Refresh, k.Quit},
+	}
+}
+
+// formKeys are the bindings while editing a card.
+type formKeys struct {
+	Save, NextField, Cancel key.Binding
+}
+
+var editKeys = formKeys{
+	Save:      key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save")),
+	NextField: key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("tab", "switch field")),
+	Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+}
+
+func (k formKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Save, k.NextField, k.Cancel}
+}
+
+func (k formKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// filterKeys are the bindings while typing a filter.
+type filterKeys struct {
+	Apply, Cancel key.Binding
+}
+
+var filteringKeys = filterKeys{
+	Apply:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "apply")),
+	Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear")),
+}
+
+func (k filterKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Apply, k.Cancel}
+}
+
+func (k filterKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
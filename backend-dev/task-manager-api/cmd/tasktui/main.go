@@ -0,0 +1,53 @@
+// Command tasktui is a kanban board for the task manager API in the
+// terminal. Cards sit in TODO, IN_PROGRESS and DONE columns; moving one
+// updates the task's status on the server, and the board reloads every few
+// seconds to pick up changes made elsewhere.
+//
+//	tasktui --server http://localhost:8080
+//
+// For a throwaway board, run the server with DATABASE_DRIVER=memory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+)
+
+func main() {
+	server := flag.String("server", envOr("TASKCTL_SERVER", "http://localhost:8080"), "server URL")
+	token := flag.String("token", os.Getenv("TASKCTL_TOKEN"), "bearer token")
+	interval := flag.Duration("refresh", 5*time.Second, "how often to reload the board")
+	flag.Parse()
+
+	if *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "tasktui: -refresh must be positive")
+		os.Exit(2)
+	}
+
+	opts := []client.Option{client.WithUserAgent("tasktui")}
+	if *token != "" {
+		opts = append(opts, client.WithAuth(client.BearerToken(*token)))
+	}
+	c, err := client.New(*server, opts...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tasktui:", err)
+		os.Exit(2)
+	}
+
+	if _, err := tea.NewProgram(newModel(c, *server, *interval), tea.WithAltScreen()).Run(); err != nil {
+		fmt.Fprintln(os.Stderr, "tasktui:", err)
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
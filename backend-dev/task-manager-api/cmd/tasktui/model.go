@@ -0,0 +1,417 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+)
+
+// columns are the board's columns, left to right.
+var columns = [...]client.TaskStatus{
+	client.TaskStatusTodo,
+	client.TaskStatusInProgress,
+	client.TaskStatusDone,
+}
+
+type mode int
+
+const (
+	browsing mode = iota
+	filtering
+	editing
+)
+
+type model struct {
+	client   *client.Client
+	server   string
+	interval time.Duration
+
+	// tasks is everything the server returned, newest first; board holds
+	// the ones that pass the filter, by column.
+	tasks []*client.Task
+	board [len(columns)][]*client.Task
+
+	// col is the selected column, and row and offset the selected card and
+	// the first visible card of each column.
+	col    int
+	row    [len(columns)]int
+	offset [len(columns)]int
+
+	mode   mode
+	filter textinput.Model
+	form   form
+	help   help.Model
+
+	width, height int
+	loading       bool
+	syncedAt      time.Time
+	// changedAt is when the board last changed locally. Loads requested
+	// before then may predate the change and are dropped.
+	changedAt     time.Time
+	status        string
+	statusIsError bool
+}
+
+// form edits the title and description of a card. id is uuid.Nil for a new
+// task, which is created in column col.
+type form struct {
+	id          uuid.UUID
+	col         int
+	title       textinput.Model
+	description textarea.Model
+}
+
+func newModel(c *client.Client, server string, interval time.Duration) model {
+	filter := textinput.New()
+	filter.Prompt = "/"
+	filter.Placeholder = "text in title or description"
+
+	return model{
+		client:   c,
+		server:   server,
+		interval: interval,
+		filter:   filter,
+		help:     help.New(),
+		loading:  true,
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(loadTasks(m.client), tick(m.interval))
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.help.Width = msg.Width
+		if m.mode == editing {
+			m.form.description.SetWidth(m.formWidth())
+		}
+		m.scroll()
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.refresh(), tick(m.interval))
+
+	case tasksLoadedMsg:
+		m.loading = false
+		if msg.requestedAt.Before(m.changedAt) {
+			return m, nil
+		}
+		m.syncedAt = msg.requestedAt
+		if m.statusIsError {
+			m.setStatus("", false)
+		}
+		m.tasks = msg.tasks
+		m.rebuild()
+		return m, nil
+
+	case taskSavedMsg:
+		m.changedAt = time.Now()
+		m.replace(msg.task)
+		m.rebuild()
+		m.selectTask(msg.task.ID)
+		return m, nil
+
+	case apiErrorMsg:
+		m.loading = false
+		m.setStatus(describe(msg.action, msg.err), true)
+		// A failed change was already shown on the board; reload to undo it.
+		if msg.action != "refresh" {
+			return m, m.refresh()
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case filtering:
+			return m.updateFilter(msg)
+		case editing:
+			return m.updateForm(msg)
+		default:
+			return m.updateBoard(msg)
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, keys.Left):
+		m.col = max(m.col-1, 0)
+	case key.Matches(msg, keys.Right):
+		m.col = min(m.col+1, len(columns)-1)
+	case key.Matches(msg, keys.Up):
+		m.row[m.col] = max(m.row[m.col]-1, 0)
+	case key.Matches(msg, keys.Down):
+		m.row[m.col] = min(m.row[m.col]+1, max(len(m.board[m.col])-1, 0))
+	case key.Matches(msg, keys.MoveLeft):
+		return m.move(-1)
+	case key.Matches(msg, keys.MoveRight):
+		return m.move(1)
+	case key.Matches(msg, keys.Edit):
+		if task := m.selected(); task != nil {
+			return m.openForm(task.ID, m.col, task.Title, task.Description)
+		}
+	case key.Matches(msg, keys.New):
+		return m.openForm(uuid.Nil, m.col, "", "")
+	case key.Matches(msg, keys.Filter):
+		m.mode = filtering
+		return m, m.filter.Focus()
+	case key.Matches(msg, keys.ClearFilter):
+		m.filter.SetValue("")
+		m.rebuild()
+	case key.Matches(msg, keys.Refresh):
+		return m, m.refresh()
+	case key.Matches(msg, keys.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	}
+
+	m.scroll()
+	return m, nil
+}
+
+// move sends the selected card dir columns over. The board changes at once
+// and is reloaded if the server refuses.
+func (m model) move(dir int) (tea.Model, tea.Cmd) {
+	task := m.selected()
+	to := m.col + dir
+	if task == nil || to < 0 || to >= len(columns) {
+		return m, nil
+	}
+
+	moved := *task
+	moved.Status = columns[to]
+	m.changedAt = time.Now()
+	m.replace(&moved)
+	m.rebuild()
+	m.selectTask(task.ID)
+
+	status := columns[to]
+	return m, updateTask(m.client, task.ID, client.UpdateTaskInput{Status: &status})
+}
+
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, filteringKeys.Apply):
+		m.mode = browsing
+		m.filter.Blur()
+		return m, nil
+	case key.Matches(msg, filteringKeys.Cancel):
+		m.mode = browsing
+		m.filter.Blur()
+		m.filter.SetValue("")
+		m.rebuild()
+		return m, nil
+	}
+
+	// Filter as the user types.
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.rebuild()
+	return m, cmd
+}
+
+func (m model) openForm(id uuid.UUID, col int, title, description string) (tea.Model, tea.Cmd) {
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.Placeholder = "Title"
+	ti.SetValue(title)
+
+	ta := textarea.New()
+	ta.Placeholder = "Description"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetWidth(m.formWidth())
+	ta.SetHeight(5)
+	ta.SetValue(description)
+
+	m.form = form{id: id, col: col, title: ti, description: ta}
+	m.mode = editing
+	return m, m.form.title.Focus()
+}
+
+func (m model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, editKeys.Cancel):
+		m.mode = browsing
+		return m, nil
+	case key.Matches(msg, editKeys.NextField):
+		if m.form.title.Focused() {
+			m.form.title.Blur()
+			return m, m.form.description.Focus()
+		}
+		m.form.description.Blur()
+		return m, m.form.title.Focus()
+	case key.Matches(msg, editKeys.Save) || (msg.Type == tea.KeyEnter && m.form.title.Focused()):
+		return m.save()
+	}
+
+	var cmd tea.Cmd
+	if m.form.title.Focused() {
+		m.form.title, cmd = m.form.title.Update(msg)
+	} else {
+		m.form.description, cmd = m.form.description.Update(msg)
+	}
+	return m, cmd
+}
+
+// save creates or updates the task from the form, sending only the fields
+// that changed.
+func (m model) save() (tea.Model, tea.Cmd) {
+	title := strings.TrimSpace(m.form.title.Value())
+	description := m.form.description.Value()
+	if title == "" {
+		m.setStatus("A task needs a title", true)
+		return m, nil
+	}
+	m.mode = browsing
+	m.setStatus("", false)
+
+	if m.form.id == uuid.Nil {
+		return m, createTask(m.client, client.CreateTaskInput{
+			Title:       title,
+			Description: description,
+			Status:      columns[m.form.col],
+		})
+	}
+
+	task := m.find(m.form.id)
+	if task == nil {
+		m.setStatus("The task was deleted while you edited it", true)
+		return m, nil
+	}
+	var input client.UpdateTaskInput
+	if title != task.Title {
+		input.Title = &title
+	}
+	if description != task.Description {
+		input.Description = &description
+	}
+	if input == (client.UpdateTaskInput{}) {
+		return m, nil
+	}
+	return m, updateTask(m.client, task.ID, input)
+}
+
+func (m *model) refresh() tea.Cmd {
+	if m.loading {
+		return nil
+	}
+	m.loading = true
+	return loadTasks(m.client)
+}
+
+func (m *model) setStatus(status string, isError bool) {
+	m.status, m.statusIsError = status, isError
+}
+
+// rebuild sorts the tasks that pass the filter into columns, keeping the
+// selected card selected if it is still there.
+func (m *model) rebuild() {
+	var selectedID uuid.UUID
+	if task := m.selected(); task != nil {
+		selectedID = task.ID
+	}
+
+	filter := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+	for i := range m.board {
+		m.board[i] = nil
+	}
+	for _, task := range m.tasks {
+		if filter != "" &&
+			!strings.Contains(strings.ToLower(task.Title), filter) &&
+			!strings.Contains(strings.ToLower(task.Description), filter) {
+			continue
+		}
+		for i, status := range columns {
+			if task.Status == status {
+				m.board[i] = append(m.board[i], task)
+			}
+		}
+	}
+
+	if selectedID == uuid.Nil || !m.selectTask(selectedID) {
+		for i := range m.row {
+			m.row[i] = min(m.row[i], max(len(m.board[i])-1, 0))
+		}
+	}
+	m.scroll()
+}
+
+// selectTask moves the selection to the card of id, reporting whether it is
+// on the board.
+func (m *model) selectTask(id uuid.UUID) bool {
+	for col, tasks := range m.board {
+		for row, task := range tasks {
+			if task.ID == id {
+				m.col, m.row[col] = col, row
+				m.scroll()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scroll keeps the selected card of every column in view.
+func (m *model) scroll() {
+	visible := m.visibleCards()
+	for i := range m.row {
+		if m.row[i] < m.offset[i] {
+			m.offset[i] = m.row[i]
+		}
+		if m.row[i] >= m.offset[i]+visible {
+			m.offset[i] = m.row[i] - visible + 1
+		}
+		m.offset[i] = max(min(m.offset[i], len(m.board[i])-visible), 0)
+	}
+}
+
+func (m *model) selected() *client.Task {
+	tasks := m.board[m.col]
+	if row := m.row[m.col]; row < len(tasks) {
+		return tasks[row]
+	}
+	return nil
+}
+
+func (m *model) find(id uuid.UUID) *client.Task {
+	for _, task := range m.tasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	return nil
+}
+
+// replace swaps in the new version of task, or adds it as the newest one.
+func (m *model) replace(task *client.Task) {
+	for i, t := range m.tasks {
+		if t.ID == task.ID {
+			m.tasks[i] = task
+			return
+		}
+	}
+	m.tasks = append([]*client.Task{task}, m.tasks...)
+}
+
+func describe(action string, err error) string {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && apiErr.Message != "" {
+		return "Could not " + action + ": " + apiErr.Message
+	}
+	return "Could not " + action + ": " + err.Error()
+}
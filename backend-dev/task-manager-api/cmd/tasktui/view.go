@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+)
+
+const (
+	// cardHeight is a card's border plus its title and detail lines.
+	cardHeight = 4
+	// columnChrome is the column header and the two scroll markers.
+	columnChrome   = 3
+	minColumnWidth = 20
+)
+
+var (
+	accent = lipgloss.Color("205")
+	subtle = lipgloss.Color("241")
+	danger = lipgloss.Color("196")
+
+	headerStyle       = lipgloss.NewStyle().Bold(true)
+	columnTitleStyle  = lipgloss.NewStyle().Bold(true).Padding(0, 1)
+	activeColumnTitle = columnTitleStyle.Foreground(accent)
+	cardStyle         = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(subtle).Padding(0, 1)
+	selectedCardStyle = cardStyle.BorderForeground(accent)
+	faintStyle        = lipgloss.NewStyle().Foreground(subtle)
+	errorStyle        = lipgloss.NewStyle().Foreground(danger)
+	formStyle         = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(accent).Padding(0, 1)
+)
+
+var columnTitles = map[client.TaskStatus]string{
+	client.TaskStatusTodo:       "To do",
+	client.TaskStatusInProgress: "In progress",
+	client.TaskStatusDone:       "Done",
+}
+
+func (m model) View() string {
+	if m.width == 0 {
+		return "Loading…"
+	}
+
+	footer := m.footer()
+	boardHeight := m.height - lipgloss.Height(footer) - 1
+
+	cols := make([]string, len(columns))
+	for i := range columns {
+		cols[i] = m.column(i, boardHeight)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		m.header(),
+		lipgloss.NewStyle().Height(boardHeight).Render(lipgloss.JoinHorizontal(lipgloss.Top, cols...)),
+		footer,
+	)
+}
+
+func (m model) header() string {
+	parts := []string{headerStyle.Render("Tasks"), m.server}
+	switch {
+	case m.loading && m.syncedAt.IsZero():
+		parts = append(parts, "loading…")
+	case !m.syncedAt.IsZero():
+		parts = append(parts, "synced "+m.syncedAt.Format(time.TimeOnly))
+	}
+	if f := m.filter.Value(); f != "" && m.mode != filtering {
+		parts = append(parts, fmt.Sprintf("filter %q", f))
+	}
+	return ansi.Truncate(strings.Join(parts, faintStyle.Render(" · ")), m.width, "…")
+}
+
+func (m model) column(i, height int) string {
+	width := m.columnWidth()
+	tasks := m.board[i]
+	active := i == m.col && m.mode != editing
+
+	titleStyle := columnTitleStyle
+	if active {
+		titleStyle = activeColumnTitle
+	}
+	lines := []string{titleStyle.Render(fmt.Sprintf("%s %d", columnTitles[columns[i]], len(tasks)))}
+
+	visible := cardsFitting(height)
+	first := min(m.offset[i], len(tasks))
+	last := min(first+visible, len(tasks))
+
+	lines = append(lines, scrollMarker("↑", first))
+	if len(tasks) == 0 {
+		lines = append(lines, faintStyle.Padding(0, 1).Render("No tasks"))
+	}
+	for row := first; row < last; row++ {
+		selected := row == m.row[i] && (active || (m.mode == editing && m.form.id == tasks[row].ID))
+		lines = append(lines, card(tasks[row], width, selected))
+	}
+	lines = append(lines, scrollMarker("↓", len(tasks)-last))
+
+	return lipgloss.NewStyle().Width(width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+func scrollMarker(arrow string, hidden int) string {
+	if hidden <= 0 {
+		return ""
+	}
+	return faintStyle.Padding(0, 1).Render(fmt.Sprintf("%s %d more", arrow, hidden))
+}
+
+func card(task *client.Task, width int, selected bool) string {
+	style := cardStyle
+	if selected {
+		style = selectedCardStyle
+	}
+	// Leave a column of space between cards, then subtract border and
+	// padding.
+	inner := width - 1 - style.GetHorizontalFrameSize()
+
+	detail := faintStyle.Render(task.ID.String()[:8])
+	if task.DueDate != nil {
+		due := "due " + task.DueDate.Local().Format("Jan 2")
+		if task.Status != client.TaskStatusDone && task.DueDate.Before(time.Now()) {
+			detail += " " + errorStyle.Render(due)
+		} else {
+			detail += faintStyle.Render(" " + due)
+		}
+	}
+
+	return style.Width(inner + style.GetHorizontalPadding()).Render(
+		ansi.Truncate(task.Title, inner, "…") + "\n" + ansi.Truncate(detail, inner, "…"),
+	)
+}
+
+func (m model) footer() string {
+	var sections []string
+
+	switch m.mode {
+	case editing:
+		sections = append(sections, m.formView(), m.help.View(editKeys))
+	case filtering:
+		sections = append(sections, m.filter.View(), m.help.View(filteringKeys))
+	default:
+		sections = append(sections, m.help.View(keys))
+	}
+
+	if m.status != "" {
+		style := faintStyle
+		if m.statusIsError {
+			style = errorStyle
+		}
+		sections = append([]string{style.Render(ansi.Truncate(m.status, m.width, "…"))}, sections...)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+func (m model) formView() string {
+	label := "Edit task"
+	if m.form.id == uuid.Nil {
+		label = "New task in " + columnTitles[columns[m.form.col]]
+	}
+	return formStyle.Width(m.formWidth() + formStyle.GetHorizontalPadding()).Render(lipgloss.JoinVertical(lipgloss.Left,
+		headerStyle.Render(label),
+		m.form.title.View(),
+		"",
+		m.form.description.View(),
+	))
+}
+
+// cardsFitting is how many cards fit in a column of the given height.
+func cardsFitting(height int) int {
+	return max((height-columnChrome)/cardHeight, 1)
+}
+
+// visibleCards is how many cards fit in the columns at the current size.
+func (m model) visibleCards() int {
+	return cardsFitting(m.height - lipgloss.Height(m.footer()) - 1)
+}
+
+func (m model) columnWidth() int {
+	return max(m.width/len(columns), minColumnWidth)
+}
+
+func (m model) formWidth() int {
+	return max(min(m.width, 100)-formStyle.GetHorizontalFrameSize(), minColumnWidth)
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// APITokenPrefix starts every API token, telling them apart from JWTs and
+// letting secret scanners spot leaked ones.
+const APITokenPrefix = "tm_"
+
+// displayLength is how much of a token is kept in the clear to recognise
+// it in listings: the prefix and 8 random characters.
+const displayLength = len(APITokenPrefix) + 8
+
+// NewAPIToken returns a random API token together with the start of it
+// shown in listings and the hash to store. The token itself must not be
+// stored.
+func NewAPIToken() (token, display, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", "", err
+	}
+	token = APITokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return token, token[:displayLength], HashAPIToken(token), nil
+}
+
+// HashAPIToken returns the hash an API token is stored and looked up by.
+// Tokens are random enough that a fast unsalted hash is safe.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIToken reports whether token looks like an API token rather than a
+// JWT.
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
@@ -0,0 +1,134 @@
+// Package auth finds out who makes a request from its bearer token. The
+// token is either a JWT from an identity provider that signs with the
+// shared secret, or an API token the API issued to a user for scripts and
+// services.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+// lastUsedResolution is how stale an API token's last use may get before
+// it is recorded again, so that busy tokens don't write on every request.
+const lastUsedResolution = time.Minute
+
+// Identity is the caller a token stands for.
+type Identity struct {
+	User string
+	// TokenID is the API token the caller used, uuid.Nil for a JWT.
+	TokenID uuid.UUID
+	// Scopes limits an API token; it is nil for a JWT, which may do
+	// whatever its user may.
+	Scopes []domain.TokenScope
+}
+
+// Context returns a copy of ctx carrying the identity for the policy.
+func (id Identity) Context(ctx context.Context) context.Context {
+	ctx = policy.WithUser(ctx, id.User)
+	if id.Scopes != nil {
+		ctx = policy.WithScopes(ctx, id.Scopes)
+	}
+	return ctx
+}
+
+// Authenticator verifies bearer tokens. A JWT must be HS256, signed with
+// the configured secret, with an expiry, and its subject is the user ID. An
+// API token must be stored in tokens, unexpired and not revoked.
+type Authenticator struct {
+	secret []byte
+	parser *jwt.Parser
+	tokens repository.APITokenRepository
+}
+
+// New returns an Authenticator for JWTs signed with secret and the API
+// tokens in tokens. With an empty secret no JWT verifies and Enabled
+// reports false.
+func New(secret string, tokens repository.APITokenRepository) *Authenticator {
+	return &Authenticator{
+		secret: []byte(secret),
+		parser: jwt.NewParser(jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired()),
+		tokens: tokens,
+	}
+}
+
+// Enabled reports whether JWTs can be verified at all.
+func (a *Authenticator) Enabled() bool {
+	return len(a.secret) > 0
+}
+
+// Checks reports whether token is checked at all: API tokens always are,
+// JWTs only while Enabled. Callers ignore the tokens it doesn't check.
+func (a *Authenticator) Checks(token string) bool {
+	return IsAPIToken(token) || a.Enabled()
+}
+
+// Authenticate returns the identity a token was issued to. It fails with
+// errs.ErrUnauthenticated when the token doesn't verify.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (Identity, error) {
+	if IsAPIToken(token) {
+		return a.apiToken(ctx, token)
+	}
+	if !a.Enabled() {
+		return Identity{}, fmt.Errorf("%w: authentication is not configured", errs.ErrUnauthenticated)
+	}
+
+	var claims jwt.RegisteredClaims
+	_, err := a.parser.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return a.secret, nil
+	})
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: invalid token: %v", errs.ErrUnauthenticated, err)
+	}
+
+	if err := domain.ValidateUserID(claims.Subject); err != nil {
+		return Identity{}, fmt.Errorf("%w: token subject is not a user ID", errs.ErrUnauthenticated)
+	}
+
+	return Identity{User: claims.Subject}, nil
+}
+
+func (a *Authenticator) apiToken(ctx context.Context, token string) (Identity, error) {
+	stored, err := a.tokens.GetByTokenHash(ctx, HashAPIToken(token))
+	if errors.Is(err, errs.ErrNotFound) {
+		return Identity{}, fmt.Errorf("%w: unknown API token", errs.ErrUnauthenticated)
+	}
+	if err != nil {
+		return Identity{}, err
+	}
+
+	now := time.Now()
+	if !stored.Active(now) {
+		return Identity{}, fmt.Errorf("%w: API token %s is expired or revoked", errs.ErrUnauthenticated, stored.ID)
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= lastUsedResolution {
+		if err := a.tokens.Touch(ctx, stored.ID, now); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Stringer("token_id", stored.ID).Msg("Failed to record API token use")
+		}
+	}
+
+	return Identity{User: stored.Owner, TokenID: stored.ID, Scopes: stored.Scopes}, nil
+}
+
+// BearerToken extracts the token from an Authorization header value. It
+// reports false when the value isn't a bearer token.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/rs/zerolog"
+)
+
+// Watcher holds the running configuration and reloads it on SIGHUP. Only
+// the log level and rate limits are picked up at runtime; changes to other
+// settings are logged and take effect after a restart.
+type Watcher struct {
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []func(cfg *Config)
+}
+
+func NewWatcher(cfg *Config) *Watcher {
+	w := &Watcher{}
+	w.current.Store(cfg)
+	return w
+}
+
+// Current returns the configuration in effect. Callers must not modify it.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// OnReload registers fn to be called with the new configuration after each
+// successful reload.
+func (w *Watcher) OnReload(fn func(cfg *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Reload loads the configuration again. When it is invalid the running
+// configuration is kept and the error returned.
+func (w *Watcher) Reload(log zerolog.Logger) error {
+	next, err := Load()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	merged, restart := w.Current().reload(next)
+	if len(restart) > 0 {
+		log.Warn().Strs("sections", restart).Msg("Configuration changes need a restart to take effect")
+	}
+
+	w.current.Store(merged)
+	for _, fn := range w.subscribers {
+		fn(merged)
+	}
+
+	log.Info().Msg("Configuration reloaded")
+	return nil
+}
+
+// Watch reloads the configuration on every SIGHUP until ctx is done.
+func (w *Watcher) Watch(ctx context.Context, log zerolog.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := w.Reload(log); err != nil {
+				log.Error().Err(err).Msg("Failed to reload configuration")
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// MaxBatchOperations caps the number of operations accepted in one batch.
+const MaxBatchOperations = 1000
+
+type BatchOpType string
+
+const (
+	BatchOpCreate BatchOpType = "create"
+	BatchOpUpdate BatchOpType = "update"
+	BatchOpDelete BatchOpType = "delete"
+)
+
+type BatchMode string
+
+const (
+	// BatchModeAllOrNothing rolls the whole batch back if any operation fails.
+	BatchModeAllOrNothing BatchMode = "all_or_nothing"
+	// BatchModeBestEffort commits every operation that succeeded and reports
+	// the failures individually.
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+// BatchOperation is a single create, update or delete inside a batch. Data
+// holds a CreateTaskInput for creates and an UpdateTaskInput for updates.
+type BatchOperation struct {
+	Op   BatchOpType     `json:"op"`
+	ID   *uuid.UUID      `json:"id,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type BatchInput struct {
+	Mode       BatchMode        `json:"mode"`
+	Operations []BatchOperation `json:"operations" binding:"required"`
+}
+
+// BatchItemResult reports one operation of a batch. Applied is true only
+// for operations whose changes were committed; ID and Task describe the
+// committed task and are left empty otherwise.
+type BatchItemResult struct {
+	Index   int         `json:"index"`
+	Op      BatchOpType `json:"op"`
+	Applied bool        `json:"applied"`
+	ID      *uuid.UUID  `json:"id,omitempty"`
+	Task    *Task       `json:"task,omitempty"`
+	Error   string      `json:"error,omitempty"`
+
+	// Err is the underlying failure; the handler turns it into Error.
+	Err error `json:"-"`
+}
+
+type BatchResult struct {
+	Mode      BatchMode         `json:"mode"`
+	Committed bool              `json:"committed"`
+	Results   []BatchItemResult `json:"results"`
+}
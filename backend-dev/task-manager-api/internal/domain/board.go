@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+// maxBoardColumns bounds how many columns a board may have.
+const maxBoardColumns = 20
+
+// Board shows tasks in columns, each holding the tasks in some statuses.
+// Every change to the definition is stored as a new Version, starting at 1.
+// A board in a project, ProjectID, shows that project's tasks; one in no
+// project shows the tasks in no project. ProjectID is set when the board is
+// created and fixed afterwards.
+type Board struct {
+	ID        uuid.UUID  `json:"id"`
+	ProjectID *uuid.UUID `json:"project_id,omitempty"`
+	BoardDefinition
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BoardDefinition is the part of a board that is versioned.
+type BoardDefinition struct {
+	Name    string        `json:"name"`
+	Columns []BoardColumn `json:"columns"`
+}
+
+// BoardColumn holds the tasks in Statuses. A WIPLimit above zero caps how
+// many tasks may be moved into it; zero means no limit.
+type BoardColumn struct {
+	Name     string       `json:"name"`
+	Statuses []TaskStatus `json:"statuses"`
+	WIPLimit int          `json:"wip_limit,omitempty"`
+}
+
+// BoardVersion is the definition a board had at Version.
+type BoardVersion struct {
+	BoardID uuid.UUID `json:"board_id"`
+	Version int       `json:"version"`
+	BoardDefinition
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateBoardInput struct {
+	ProjectID *uuid.UUID `json:"project_id,omitempty"`
+	BoardDefinition
+}
+
+// UpdateBoardInput changes the fields present in the body. Version is the
+// version the change was made against; it fails if the board has changed
+// since.
+type UpdateBoardInput struct {
+	Version int            `json:"version" binding:"required"`
+	Name    *string        `json:"name,omitempty"`
+	Columns *[]BoardColumn `json:"columns,omitempty"`
+}
+
+// BoardColumnPage is one page of the tasks in a board column, in rank
+// order. Count is the number of tasks in the whole column. NextCursor, set
+// when there are more tasks, fetches the next page of the column.
+type BoardColumnPage struct {
+	BoardColumn
+	Count      int     `json:"count"`
+	Tasks      []*Task `json:"tasks"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+func (in CreateBoardInput) Validate() error {
+	return in.BoardDefinition.Validate()
+}
+
+func (in UpdateBoardInput) Validate() error {
+	if in.Version < 1 {
+		return fmt.Errorf("%w: version is required", errs.ErrInvalidInput)
+	}
+	// The definition is checked as a whole once applied to the board.
+	return nil
+}
+
+// Validate checks that the board has uniquely named columns and that each
+// status shows in at most one of them.
+func (d BoardDefinition) Validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidInput)
+	}
+	if len(d.Name) > 255 {
+		return fmt.Errorf("%w: name is longer than 255 bytes", errs.ErrInvalidInput)
+	}
+	if len(d.Columns) == 0 {
+		return fmt.Errorf("%w: a board needs at least one column", errs.ErrInvalidInput)
+	}
+	if len(d.Columns) > maxBoardColumns {
+		return fmt.Errorf("%w: a board has at most %d columns", errs.ErrInvalidInput, maxBoardColumns)
+	}
+
+	names := make(map[string]bool, len(d.Columns))
+	statuses := make(map[TaskStatus]string)
+	for _, column := range d.Columns {
+		if strings.TrimSpace(column.Name) == "" {
+			return fmt.Errorf("%w: column name is required", errs.ErrInvalidInput)
+		}
+		if names[column.Name] {
+			return fmt.Errorf("%w: column %q is repeated", errs.ErrInvalidInput, column.Name)
+		}
+		names[column.Name] = true
+
+		if len(column.Statuses) == 0 {
+			return fmt.Errorf("%w: column %q has no statuses", errs.ErrInvalidInput, column.Name)
+		}
+		for _, status := range column.Statuses {
+			if !status.Valid() {
+				return fmt.Errorf("%w: unknown status %q", errs.ErrInvalidInput, status)
+			}
+			if other, ok := statuses[status]; ok {
+				return fmt.Errorf("%w: status %s is in both column %q and column %q", errs.ErrInvalidInput, status, other, column.Name)
+			}
+			statuses[status] = column.Name
+		}
+
+		if column.WIPLimit < 0 {
+			return fmt.Errorf("%w: WIP limit of column %q is negative", errs.ErrInvalidInput, column.Name)
+		}
+	}
+
+	return nil
+}
+
+// Column returns the column named name, or nil.
+func (d BoardDefinition) Column(name string) *BoardColumn {
+	for i := range d.Columns {
+		if d.Columns[i].Name == name {
+			return &d.Columns[i]
+		}
+	}
+	return nil
+}
+
+// TaskFilter matches the tasks the board shows in column.
+func (b *Board) TaskFilter(column BoardColumn) TaskFilter {
+	return TaskFilter{Statuses: column.Statuses, ProjectID: b.ProjectID, NoProject: b.ProjectID == nil}
+}
+
+// ColumnFor returns the column holding tasks in status, or nil when the
+// board doesn't show them.
+func (d BoardDefinition) ColumnFor(status TaskStatus) *BoardColumn {
+	for i := range d.Columns {
+		if slices.Contains(d.Columns[i].Statuses, status) {
+			return &d.Columns[i]
+		}
+	}
+	return nil
+}
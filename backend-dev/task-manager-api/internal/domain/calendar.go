@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed grants read access to the iCalendar feed of task due dates.
+// Only a hash of its secret token is stored.
+type CalendarFeed struct {
+	ID        uuid.UUID `json:"id"`
+	Owner     string    `json:"owner"`
+	TokenHash string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateCalendarFeedInput struct {
+	Owner string `json:"owner" binding:"required"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskEventType string
+
+const (
+	TaskEventCreated TaskEventType = "created"
+	TaskEventUpdated TaskEventType = "updated"
+	TaskEventDeleted TaskEventType = "deleted"
+)
+
+// TaskEvent describes a committed change to a task. Task is the state after
+// the change and nil for deletions.
+type TaskEvent struct {
+	Type   TaskEventType
+	TaskID uuid.UUID
+	Task   *Task
+	Time   time.Time
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskChange is what a task history entry records.
+type TaskChange string
+
+const (
+	TaskChangeUserAdded   TaskChange = "user_added"
+	TaskChangeUserRemoved TaskChange = "user_removed"
+)
+
+// TaskHistoryEntry records a change Actor made to a task. So far only
+// changes to assignees and watchers are recorded: User was added or removed
+// as Relation. Actor is empty for changes made without a user.
+type TaskHistoryEntry struct {
+	ID        uuid.UUID    `json:"id"`
+	TaskID    uuid.UUID    `json:"task_id"`
+	Change    TaskChange   `json:"change"`
+	Relation  TaskRelation `json:"relation"`
+	User      string       `json:"user"`
+	Actor     string       `json:"actor,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord remembers the outcome of a request made with an
+// Idempotency-Key header. StatusCode is zero while the original request is
+// still being processed.
+type IdempotencyRecord struct {
+	Key          string
+	Fingerprint  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	LockedAt     time.Time
+	CreatedAt    time.Time
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,21 @@
+package domain
+
+// ImportRow is one parsed line of an import file. Err is set when the line
+// could not be decoded into Input at all.
+type ImportRow struct {
+	Line  int
+	Input CreateTaskInput
+	Err   error
+}
+
+type ImportLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type ImportResult struct {
+	DryRun   bool              `json:"dry_run"`
+	Total    int               `json:"total"`
+	Imported int               `json:"imported"`
+	Errors   []ImportLineError `json:"errors"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// NotificationKind is what a notification is about.
+type NotificationKind string
+
+const (
+	NotificationAssigned NotificationKind = "assigned"
+)
+
+// Notification tells User that Actor did something to Task.
+type Notification struct {
+	Kind      NotificationKind
+	User      string
+	Actor     string
+	Task      *Task
+	CreatedAt time.Time
+}
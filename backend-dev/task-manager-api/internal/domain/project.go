@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+// Project groups tasks. What a user may do with a project and its tasks
+// depends on their role in it; see internal/policy.
+type Project struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProjectRole is a user's role in a project, from owner down to viewer.
+type ProjectRole string
+
+const (
+	ProjectRoleOwner      ProjectRole = "owner"
+	ProjectRoleMaintainer ProjectRole = "maintainer"
+	ProjectRoleMember     ProjectRole = "member"
+	ProjectRoleViewer     ProjectRole = "viewer"
+)
+
+// ProjectRoles lists every role, most powerful first.
+var ProjectRoles = []ProjectRole{ProjectRoleOwner, ProjectRoleMaintainer, ProjectRoleMember, ProjectRoleViewer}
+
+// Membership gives User a Role in a project.
+type Membership struct {
+	ProjectID uuid.UUID   `json:"project_id"`
+	User      string      `json:"user"`
+	Role      ProjectRole `json:"role"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type CreateProjectInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type UpdateProjectInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MembershipInput sets the role of the user named in the path.
+type MembershipInput struct {
+	Role ProjectRole `json:"role" binding:"required"`
+}
+
+func (r ProjectRole) Valid() bool {
+	switch r {
+	case ProjectRoleOwner, ProjectRoleMaintainer, ProjectRoleMember, ProjectRoleViewer:
+		return true
+	}
+	return false
+}
+
+func validateProjectName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidInput)
+	}
+	if len(name) > 255 {
+		return fmt.Errorf("%w: name is longer than 255 bytes", errs.ErrInvalidInput)
+	}
+	return nil
+}
+
+func (in CreateProjectInput) Validate() error {
+	return validateProjectName(in.Name)
+}
+
+func (in UpdateProjectInput) Validate() error {
+	return validateProjectName(in.Name)
+}
+
+func (in MembershipInput) Validate() error {
+	if !in.Role.Valid() {
+		return fmt.Errorf("%w: unknown role %q", errs.ErrInvalidInput, in.Role)
+	}
+	return nil
+}
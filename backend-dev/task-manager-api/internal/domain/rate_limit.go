@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimitPolicy allows Requests requests per Period on average, with
+// bursts of up to Burst requests.
+type RateLimitPolicy struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+// RefillInterval is the time it takes to earn back one request.
+func (p RateLimitPolicy) RefillInterval() time.Duration {
+	return p.Period / time.Duration(p.Requests)
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the bucket is full again.
+	ResetAfter time.Duration
+	// RetryAfter is the time until the next request would be allowed; it is
+	// zero when Allowed is true.
+	RetryAfter time.Duration
+}
+
+// TokenBucket is the persisted state of one rate limit bucket.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewTokenBucket returns a full bucket for policy.
+func NewTokenBucket(policy RateLimitPolicy, now time.Time) TokenBucket {
+	return TokenBucket{Tokens: float64(policy.Burst), UpdatedAt: now}
+}
+
+// Take refills the bucket for the time elapsed since it was last used and
+// then tries to take one token out of it.
+func (b *TokenBucket) Take(policy RateLimitPolicy, now time.Time) RateLimitResult {
+	interval := policy.RefillInterval()
+
+	if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = math.Min(float64(policy.Burst), b.Tokens+float64(elapsed)/float64(interval))
+		b.UpdatedAt = now
+	}
+
+	result := RateLimitResult{Limit: policy.Burst}
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.Tokens) * float64(interval))
+	}
+
+	result.Remaining = int(b.Tokens)
+	result.ResetAfter = time.Duration((float64(policy.Burst) - b.Tokens) * float64(interval))
+
+	return result
+}
@@ -1,9 +1,13 @@
 package domain
 
 import (
+	"encoding/base64"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
 )
 
 type TaskStatus string
@@ -14,26 +18,239 @@ const (
 	TaskStatusDone       TaskStatus = "DONE"
 )
 
+// TaskPriority runs from P0, the most urgent, to P3.
+type TaskPriority string
+
+const (
+	TaskPriorityP0 TaskPriority = "P0"
+	TaskPriorityP1 TaskPriority = "P1"
+	TaskPriorityP2 TaskPriority = "P2"
+	TaskPriorityP3 TaskPriority = "P3"
+
+	// DefaultTaskPriority is given to tasks created without one.
+	DefaultTaskPriority = TaskPriorityP2
+)
+
+// Task is ordered manually by Rank, a fractional-index key (see pkg/rank)
+// managed by the server. Tasks that predate ranks have an empty one until
+// the next rebalance. Assignees and Watchers are user IDs, sorted, and
+// only change through the assign and watch endpoints. ProjectID is set when
+// the task is created and doesn't change; with the policy enforced, tasks
+// in no project are open to nobody.
 type Task struct {
-	ID          uuid.UUID  `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          uuid.UUID    `json:"id"`
+	ProjectID   *uuid.UUID   `json:"project_id,omitempty"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	Rank        string       `json:"rank"`
+	Assignees   []string     `json:"assignees,omitempty"`
+	Watchers    []string     `json:"watchers,omitempty"`
+	DueDate     *time.Time   `json:"due_date,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TaskRelation is how a user is involved in a task.
+type TaskRelation string
+
+const (
+	TaskRelationAssignee TaskRelation = "assignee"
+	TaskRelationWatcher  TaskRelation = "watcher"
+)
+
+// maxUserIDLength matches the user_id column of task_people.
+const maxUserIDLength = 255
+
+// ValidateUserID checks a user ID given to assign or watch a task.
+func ValidateUserID(user string) error {
+	if strings.TrimSpace(user) == "" {
+		return fmt.Errorf("%w: user is required", errs.ErrInvalidInput)
+	}
+	if user != strings.TrimSpace(user) {
+		return fmt.Errorf("%w: user cannot start or end with whitespace", errs.ErrInvalidInput)
+	}
+	if strings.Contains(user, "/") {
+		return fmt.Errorf("%w: user cannot contain '/'", errs.ErrInvalidInput)
+	}
+	if len(user) > maxUserIDLength {
+		return fmt.Errorf("%w: user is longer than %d bytes", errs.ErrInvalidInput, maxUserIDLength)
+	}
+	return nil
 }
 
 type CreateTaskInput struct {
-	Title       string     `json:"title" binding:"required"`
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
+	ProjectID   *uuid.UUID   `json:"project_id,omitempty"`
+	Title       string       `json:"title" binding:"required"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	DueDate     *time.Time   `json:"due_date,omitempty"`
 }
 
+// UpdateTaskInput changes the fields that are set. A status change that
+// would take a board column over its WIP limit is refused unless
+// OverrideWIPLimit is set.
 type UpdateTaskInput struct {
-	Title       *string     `json:"title,omitempty"`
-	Description *string     `json:"description,omitempty"`
-	Status      *TaskStatus `json:"status,omitempty"`
-	DueDate     *time.Time  `json:"due_date,omitempty"`
+	Title            *string       `json:"title,omitempty"`
+	Description      *string       `json:"description,omitempty"`
+	Status           *TaskStatus   `json:"status,omitempty"`
+	Priority         *TaskPriority `json:"priority,omitempty"`
+	DueDate          *time.Time    `json:"due_date,omitempty"`
+	OverrideWIPLimit bool          `json:"override_wip_limit,omitempty"`
+}
+
+// TaskUserInput names the user to assign to or watch a task.
+type TaskUserInput struct {
+	User string `json:"user" binding:"required"`
+}
+
+// MoveTaskInput places a task right after After, right before Before, or
+// between the two. At least one of them is required.
+type MoveTaskInput struct {
+	Before *uuid.UUID `json:"before,omitempty"`
+	After  *uuid.UUID `json:"after,omitempty"`
+}
+
+// TaskStatuses lists every status in workflow order.
+var TaskStatuses = []TaskStatus{TaskStatusTodo, TaskStatusInProgress, TaskStatusDone}
+
+// TaskPriorities lists every priority, most urgent first.
+var TaskPriorities = []TaskPriority{TaskPriorityP0, TaskPriorityP1, TaskPriorityP2, TaskPriorityP3}
+
+// TaskStats summarizes the task table for monitoring.
+type TaskStats struct {
+	ByStatus map[TaskStatus]int
+	Overdue  int
+}
+
+// RankStats tells whether the task ranks need rebalancing.
+type RankStats struct {
+	MaxLength int
+	Unranked  int
+}
+
+// TaskFilter narrows down which tasks a query returns. The zero value
+// matches every task. Search matches title or description, ignoring case.
+// Assignee matches tasks assigned to that user, Unassigned those assigned
+// to nobody. ProjectID matches the tasks of one project, NoProject those in
+// no project; with RestrictProjects only tasks in one of Projects match.
+// Results come newest first, or in rank order with RankOrder; After and
+// Limit page through them.
+type TaskFilter struct {
+	IDs        []uuid.UUID
+	Statuses   []TaskStatus
+	HasDueDate bool
+	Search     string
+	Assignee   string
+	Unassigned bool
+	ProjectID  *uuid.UUID
+	NoProject  bool
+
+	RestrictProjects bool
+	Projects         []uuid.UUID
+
+	RankOrder bool
+	After     *TaskCursor
+	Limit     int
+}
+
+// TaskCursor is a position in the newest-first or the rank order of tasks;
+// the newest-first order ignores Rank.
+type TaskCursor struct {
+	Rank      string
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Cursor is the position of t in either task order.
+func (t *Task) Cursor() TaskCursor {
+	return TaskCursor{Rank: t.Rank, CreatedAt: t.CreatedAt, ID: t.ID}
+}
+
+// String encodes the cursor as an opaque token for clients.
+func (c TaskCursor) String() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String() + "|" + c.Rank
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTaskCursor decodes a token made by TaskCursor.String.
+func ParseTaskCursor(token string) (*TaskCursor, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", errs.ErrInvalidInput)
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	// Tokens made before cursors carried the rank have no third part.
+	createdAt, rest, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, invalid
+	}
+	id, rank, _ := strings.Cut(rest, "|")
+
+	c := TaskCursor{Rank: rank}
+	if c.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, invalid
+	}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return nil, invalid
+	}
+	return &c, nil
+}
+
+func (s TaskStatus) Valid() bool {
+	switch s {
+	case TaskStatusTodo, TaskStatusInProgress, TaskStatusDone:
+		return true
+	}
+	return false
+}
+
+func (p TaskPriority) Valid() bool {
+	switch p {
+	case TaskPriorityP0, TaskPriorityP1, TaskPriorityP2, TaskPriorityP3:
+		return true
+	}
+	return false
+}
+
+func (in CreateTaskInput) Validate() error {
+	if strings.TrimSpace(in.Title) == "" {
+		return fmt.Errorf("%w: title is required", errs.ErrInvalidInput)
+	}
+	if in.Status != "" && !in.Status.Valid() {
+		return fmt.Errorf("%w: unknown status %q", errs.ErrInvalidInput, in.Status)
+	}
+	if in.Priority != "" && !in.Priority.Valid() {
+		return fmt.Errorf("%w: unknown priority %q", errs.ErrInvalidInput, in.Priority)
+	}
+	return nil
+}
+
+func (in UpdateTaskInput) Validate() error {
+	if in.Title != nil && strings.TrimSpace(*in.Title) == "" {
+		return fmt.Errorf("%w: title cannot be empty", errs.ErrInvalidInput)
+	}
+	if in.Status != nil && !in.Status.Valid() {
+		return fmt.Errorf("%w: unknown status %q", errs.ErrInvalidInput, *in.Status)
+	}
+	if in.Priority != nil && !in.Priority.Valid() {
+		return fmt.Errorf("%w: unknown priority %q", errs.ErrInvalidInput, *in.Priority)
+	}
+	return nil
+}
+
+func (in MoveTaskInput) Validate(id uuid.UUID) error {
+	if in.Before == nil && in.After == nil {
+		return fmt.Errorf("%w: before or after is required", errs.ErrInvalidInput)
+	}
+	if (in.Before != nil && *in.Before == id) || (in.After != nil && *in.After == id) {
+		return fmt.Errorf("%w: a task cannot be moved next to itself", errs.ErrInvalidInput)
+	}
+	if in.Before != nil && in.After != nil && *in.Before == *in.After {
+		return fmt.Errorf("%w: before and after must be different tasks", errs.ErrInvalidInput)
+	}
+	return nil
 }
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+// ViewSchemaVersion is the version of the task fields view definitions may
+// refer to. Bump it when a status, sort field or column is renamed or
+// removed; views saved under an older version are revalidated on startup.
+const ViewSchemaVersion = 1
+
+// View is a saved task query: a filter, a sort order and the columns to
+// show. It belongs to Owner and, when Team is set, is shared with that team.
+type View struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Owner string    `json:"owner"`
+	Team  string    `json:"team,omitempty"`
+	ViewDefinition
+
+	// SchemaVersion is the ViewSchemaVersion the definition was last
+	// checked against, and Problem why it no longer validates, if it
+	// doesn't.
+	SchemaVersion int       `json:"schema_version"`
+	Problem       string    `json:"problem,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ViewDefinition is the parsed query a view runs. It is stored as is, so
+// views keep working when the query string syntax of the list endpoints
+// changes.
+type ViewDefinition struct {
+	Filter  ViewFilter `json:"filter"`
+	Sort    []ViewSort `json:"sort,omitempty"`
+	Columns []string   `json:"columns,omitempty"`
+}
+
+type ViewFilter struct {
+	Statuses   []TaskStatus `json:"statuses,omitempty"`
+	HasDueDate bool         `json:"has_due_date,omitempty"`
+	Search     string       `json:"search,omitempty"`
+}
+
+// ViewSort orders tasks by Field, ascending unless Desc is set. Tasks
+// without a due date sort last either way.
+type ViewSort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc,omitempty"`
+}
+
+type CreateViewInput struct {
+	Name  string `json:"name" binding:"required"`
+	Owner string `json:"owner" binding:"required"`
+	Team  string `json:"team"`
+	ViewDefinition
+}
+
+// UpdateViewInput changes the fields present in the body. An empty Team
+// stops sharing the view.
+type UpdateViewInput struct {
+	Name    *string     `json:"name,omitempty"`
+	Team    *string     `json:"team,omitempty"`
+	Filter  *ViewFilter `json:"filter,omitempty"`
+	Sort    *[]ViewSort `json:"sort,omitempty"`
+	Columns *[]string   `json:"columns,omitempty"`
+}
+
+// viewColumns maps the columns a view may show to the task field values.
+var viewColumns = map[string]func(t *Task) any{
+	"id":          func(t *Task) any { return t.ID },
+	"title":       func(t *Task) any { return t.Title },
+	"description": func(t *Task) any { return t.Description },
+	"status":      func(t *Task) any { return t.Status },
+	"priority":    func(t *Task) any { return t.Priority },
+	"rank":        func(t *Task) any { return t.Rank },
+	"due_date":    func(t *Task) any { return t.DueDate },
+	"created_at":  func(t *Task) any { return t.CreatedAt },
+	"updated_at":  func(t *Task) any { return t.UpdatedAt },
+}
+
+// viewSortFields compares two tasks by each field a view may sort on.
+var viewSortFields = map[string]func(a, b *Task) int{
+	"title":      func(a, b *Task) int { return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)) },
+	"status":     func(a, b *Task) int { return statusRank(a.Status) - statusRank(b.Status) },
+	"priority":   func(a, b *Task) int { return strings.Compare(string(a.Priority), string(b.Priority)) },
+	"rank":       func(a, b *Task) int { return strings.Compare(a.Rank, b.Rank) },
+	"due_date":   func(a, b *Task) int { return a.DueDate.Compare(*b.DueDate) },
+	"created_at": func(a, b *Task) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updated_at": func(a, b *Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+}
+
+// statusRank orders statuses as in TaskStatuses.
+func statusRank(s TaskStatus) int {
+	for i, status := range TaskStatuses {
+		if s == status {
+			return i
+		}
+	}
+	return len(TaskStatuses)
+}
+
+func (in CreateViewInput) Validate() error {
+	if err := validateViewName(in.Name); err != nil {
+		return err
+	}
+	if strings.TrimSpace(in.Owner) == "" {
+		return fmt.Errorf("%w: owner is required", errs.ErrInvalidInput)
+	}
+	return in.ViewDefinition.Validate()
+}
+
+func (in UpdateViewInput) Validate() error {
+	if in.Name != nil {
+		if err := validateViewName(*in.Name); err != nil {
+			return err
+		}
+	}
+	// The definition is checked as a whole once applied to the view.
+	return nil
+}
+
+func validateViewName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidInput)
+	}
+	if len(name) > 255 {
+		return fmt.Errorf("%w: name is longer than 255 bytes", errs.ErrInvalidInput)
+	}
+	return nil
+}
+
+// Validate checks that the definition only refers to statuses, sort fields
+// and columns that exist.
+func (d ViewDefinition) Validate() error {
+	for _, status := range d.Filter.Statuses {
+		if !status.Valid() {
+			return fmt.Errorf("%w: unknown status %q", errs.ErrInvalidInput, status)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range d.Sort {
+		if viewSortFields[s.Field] == nil {
+			return fmt.Errorf("%w: cannot sort by %q", errs.ErrInvalidInput, s.Field)
+		}
+		if seen[s.Field] {
+			return fmt.Errorf("%w: sort field %q is repeated", errs.ErrInvalidInput, s.Field)
+		}
+		seen[s.Field] = true
+	}
+
+	clear(seen)
+	for _, column := range d.Columns {
+		if viewColumns[column] == nil {
+			return fmt.Errorf("%w: unknown column %q", errs.ErrInvalidInput, column)
+		}
+		if seen[column] {
+			return fmt.Errorf("%w: column %q is repeated", errs.ErrInvalidInput, column)
+		}
+		seen[column] = true
+	}
+
+	return nil
+}
+
+// TaskFilter is the repository filter selecting the view's tasks.
+func (d ViewDefinition) TaskFilter() TaskFilter {
+	return TaskFilter{
+		Statuses:   d.Filter.Statuses,
+		HasDueDate: d.Filter.HasDueDate,
+		Search:     d.Filter.Search,
+	}
+}
+
+// SortTasks puts tasks in the view's order. Ties keep their existing order,
+// so with no sort the tasks stay newest first.
+func (d ViewDefinition) SortTasks(tasks []*Task) {
+	if len(d.Sort) == 0 {
+		return
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		for _, s := range d.Sort {
+			if s.Field == "due_date" && (a.DueDate == nil || b.DueDate == nil) {
+				if (a.DueDate == nil) != (b.DueDate == nil) {
+					return b.DueDate == nil
+				}
+				continue
+			}
+			c := viewSortFields[s.Field](a, b)
+			if s.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// Row holds the view's columns of task, always including its ID. Without
+// columns it is the whole task.
+func (d ViewDefinition) Row(task *Task) any {
+	if len(d.Columns) == 0 {
+		return task
+	}
+	row := map[string]any{"id": task.ID}
+	for _, column := range d.Columns {
+		row[column] = viewColumns[column](task)
+	}
+	return row
+}
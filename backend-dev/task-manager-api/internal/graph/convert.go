@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/graph/model"
+)
+
+func parseID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid task ID", errs.ErrInvalidInput)
+	}
+	return id, nil
+}
+
+func parseOptionalID(raw *string) (*uuid.UUID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	id, err := parseID(*raw)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func parseProjectID(raw *string) (*uuid.UUID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid project ID", errs.ErrInvalidInput)
+	}
+	return &id, nil
+}
+
+func taskToModel(task *domain.Task) *model.Task {
+	out := &model.Task{
+		ID:          task.ID.String(),
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      model.TaskStatus(task.Status),
+		Priority:    model.TaskPriority(task.Priority),
+		Rank:        task.Rank,
+		Assignees:   nonNil(task.Assignees),
+		Watchers:    nonNil(task.Watchers),
+		DueDate:     task.DueDate,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}
+	if task.ProjectID != nil {
+		id := task.ProjectID.String()
+		out.ProjectID = &id
+	}
+	return out
+}
+
+// nonNil returns an empty slice for nil, as non-null lists can't be null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func createInputFromModel(in model.CreateTaskInput) (domain.CreateTaskInput, error) {
+	projectID, err := parseProjectID(in.ProjectID)
+	if err != nil {
+		return domain.CreateTaskInput{}, err
+	}
+
+	input := domain.CreateTaskInput{
+		ProjectID: projectID,
+		Title:     in.Title,
+		DueDate:   in.DueDate,
+	}
+	if in.Description != nil {
+		input.Description = *in.Description
+	}
+	if in.Status != nil {
+		input.Status = domain.TaskStatus(*in.Status)
+	}
+	if in.Priority != nil {
+		input.Priority = domain.TaskPriority(*in.Priority)
+	}
+	return input, nil
+}
+
+func updateInputFromModel(in model.UpdateTaskInput) domain.UpdateTaskInput {
+	input := domain.UpdateTaskInput{
+		Title:       in.Title,
+		Description: in.Description,
+		DueDate:     in.DueDate,
+	}
+	if in.Status != nil {
+		status := domain.TaskStatus(*in.Status)
+		input.Status = &status
+	}
+	if in.Priority != nil {
+		priority := domain.TaskPriority(*in.Priority)
+		input.Priority = &priority
+	}
+	return input
+}
+
+func filterFromModel(in *model.TaskFilter) (domain.TaskFilter, error) {
+	var filter domain.TaskFilter
+	if in == nil {
+		return filter, nil
+	}
+	projectID, err := parseProjectID(in.ProjectID)
+	if err != nil {
+		return filter, err
+	}
+	filter.ProjectID = projectID
+	for _, s := range in.Statuses {
+		filter.Statuses = append(filter.Statuses, domain.TaskStatus(s))
+	}
+	if in.HasDueDate != nil {
+		filter.HasDueDate = *in.HasDueDate
+	}
+	if in.Search != nil {
+		filter.Search = strings.TrimSpace(*in.Search)
+	}
+	return filter, nil
+}
+
+var eventTypes = map[domain.TaskEventType]model.TaskEventType{
+	domain.TaskEventCreated: model.TaskEventTypeCreated,
+	domain.TaskEventUpdated: model.TaskEventTypeUpdated,
+	domain.TaskEventDeleted: model.TaskEventTypeDeleted,
+}
+
+func eventToModel(event domain.TaskEvent) *model.TaskEvent {
+	out := &model.TaskEvent{
+		Type:   eventTypes[event.Type],
+		TaskID: event.TaskID.String(),
+		Time:   event.Time,
+	}
+	if event.Task != nil {
+		out.Task = taskToModel(event.Task)
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/rs/zerolog"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes reported in the "code" extension of GraphQL errors.
+const (
+	codeNotFound        = "NOT_FOUND"
+	codeInvalidInput    = "BAD_USER_INPUT"
+	codeConflict        = "CONFLICT"
+	codeUnauthenticated = "UNAUTHENTICATED"
+	codeForbidden       = "FORBIDDEN"
+	codeInternal        = "INTERNAL_SERVER_ERROR"
+)
+
+// toGQLError maps service errors to GraphQL errors with a code extension.
+// Unexpected errors are logged and reported without their details, as the
+// REST API does with 500s.
+func toGQLError(ctx context.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		return gqlError(err.Error(), codeNotFound)
+	case errors.Is(err, errs.ErrInvalidInput):
+		return gqlError(err.Error(), codeInvalidInput)
+	case errors.Is(err, errs.ErrConflict):
+		return gqlError(err.Error(), codeConflict)
+	case errors.Is(err, errs.ErrUnauthenticated):
+		return gqlError(err.Error(), codeUnauthenticated)
+	case errors.Is(err, errs.ErrForbidden):
+		return gqlError(err.Error(), codeForbidden)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return err
+	}
+
+	zerolog.Ctx(ctx).Error().Err(err).Msg(msg)
+	return gqlError(msg, codeInternal)
+}
+
+func gqlError(msg, code string) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    msg,
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
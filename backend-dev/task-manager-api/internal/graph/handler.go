@@ -0,0 +1,53 @@
+// Package graph serves the GraphQL API on top of the same TaskService as
+// the REST handlers. The schema lives in schema.graphqls; run `make graphql`
+// after changing it.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"github.com/rs/zerolog"
+)
+
+// NewHandler serves queries and mutations over HTTP and subscriptions over
+// WebSocket, rejecting operations beyond the configured depth and
+// complexity before any resolver runs.
+func NewHandler(tasks *service.TaskService, cfg config.GraphQLConfig) http.Handler {
+	srv := handler.New(NewExecutableSchema(Config{
+		Resolvers:  NewResolver(tasks),
+		Complexity: complexity(),
+	}))
+
+	srv.AddTransport(transport.Websocket{KeepAlivePingInterval: 10 * time.Second})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+
+	srv.SetQueryCache(lru.New(1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(depthLimit{max: cfg.MaxDepth})
+	srv.Use(extension.FixedComplexityLimit(cfg.MaxComplexity))
+
+	srv.SetRecoverFunc(func(ctx context.Context, p interface{}) error {
+		zerolog.Ctx(ctx).Error().Str("panic", fmt.Sprint(p)).Msg("GraphQL resolver panicked")
+		return gqlError("Internal server error", codeInternal)
+	})
+
+	return withLoaders(tasks, srv)
+}
+
+// PlaygroundHandler serves an in-browser IDE for the API at endpoint.
+func PlaygroundHandler(endpoint string) http.Handler {
+	return playground.Handler("Task Manager API", endpoint)
+}
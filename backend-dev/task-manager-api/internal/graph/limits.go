@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/graph/model"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func pageSize(first *int) (int, error) {
+	if first == nil {
+		return defaultPageSize, nil
+	}
+	if *first < 1 || *first > maxPageSize {
+		return 0, fmt.Errorf("%w: first must be between 1 and %d", errs.ErrInvalidInput, maxPageSize)
+	}
+	return *first, nil
+}
+
+// complexity prices list fields by the page they ask for, so one query
+// can't fan out into thousands of rows.
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+	c.Query.Tasks = func(childComplexity int, _ *model.TaskFilter, first *int, _ *string) int {
+		n, err := pageSize(first)
+		if err != nil {
+			// Rejected by the resolver anyway; price it as a full page.
+			n = maxPageSize
+		}
+		return 1 + n*childComplexity
+	}
+	return c
+}
+
+// depthLimit rejects operations whose selections nest deeper than max.
+// Introspection is exempt: the playground's schema query is deep by design
+// and cheap to answer.
+type depthLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = depthLimit{}
+
+func (depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (depthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d depthLimit) MutateOperationContext(_ context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if depth := selectionDepth(rc.Operation.SelectionSet); depth > d.max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.max)
+		err.Extensions = map[string]interface{}{"code": "DEPTH_LIMIT_EXCEEDED"}
+		return err
+	}
+	return nil
+}
+
+// selectionDepth counts nested fields; fragments add no depth of their own.
+// Fragment cycles are rejected by validation before this runs.
+func selectionDepth(set ast.SelectionSet) int {
+	depth := 0
+	for _, sel := range set {
+		var d int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				d = selectionDepth(sel.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
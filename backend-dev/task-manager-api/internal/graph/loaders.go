@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/dataloader"
+)
+
+const (
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 100
+)
+
+type loadersKey struct{}
+
+// Loaders batches and caches lookups for the lifetime of one request.
+type Loaders struct {
+	Task *dataloader.Loader[uuid.UUID, *domain.Task]
+}
+
+func newLoaders(tasks *service.TaskService) *Loaders {
+	return &Loaders{
+		Task: dataloader.New(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Task, error) {
+			found, err := tasks.FindTasks(ctx, domain.TaskFilter{IDs: ids})
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[uuid.UUID]*domain.Task, len(found))
+			for _, task := range found {
+				byID[task.ID] = task
+			}
+			return byID, nil
+		}, loaderWait, loaderMaxBatch),
+	}
+}
+
+// withLoaders gives each request its own Loaders, so cached results never
+// leak between requests.
+func withLoaders(tasks *service.TaskService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, newLoaders(tasks))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func loadersFor(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
@@ -0,0 +1,219 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type CreateTaskInput struct {
+	Title       string      `json:"title"`
+	Description *string     `json:"description,omitempty"`
+	Status      *TaskStatus `json:"status,omitempty"`
+	// Defaults to P2.
+	Priority *TaskPriority `json:"priority,omitempty"`
+	// The project the task belongs to, for good.
+	ProjectID *string    `json:"projectId,omitempty"`
+	DueDate   *time.Time `json:"dueDate,omitempty"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
+type Query struct {
+}
+
+type Subscription struct {
+}
+
+type Task struct {
+	ID          string       `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	// Sorting by rank as plain strings gives the manual ordering.
+	Rank string `json:"rank"`
+	// Null for tasks in no project.
+	ProjectID *string `json:"projectId,omitempty"`
+	// User IDs, changed only through assignTask and friends.
+	Assignees []string   `json:"assignees"`
+	Watchers  []string   `json:"watchers"`
+	DueDate   *time.Time `json:"dueDate,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+type TaskConnection struct {
+	Edges    []*TaskEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type TaskEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Task  `json:"node"`
+}
+
+type TaskEvent struct {
+	Type   TaskEventType `json:"type"`
+	TaskID string        `json:"taskId"`
+	// The task after the change; null for deletions.
+	Task *Task     `json:"task,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+type TaskFilter struct {
+	ProjectID  *string      `json:"projectId,omitempty"`
+	Statuses   []TaskStatus `json:"statuses,omitempty"`
+	HasDueDate *bool        `json:"hasDueDate,omitempty"`
+	// Matches title or description, ignoring case.
+	Search *string `json:"search,omitempty"`
+}
+
+type UpdateTaskInput struct {
+	Title       *string       `json:"title,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Status      *TaskStatus   `json:"status,omitempty"`
+	Priority    *TaskPriority `json:"priority,omitempty"`
+	DueDate     *time.Time    `json:"dueDate,omitempty"`
+}
+
+type TaskEventType string
+
+const (
+	TaskEventTypeCreated TaskEventType = "CREATED"
+	TaskEventTypeUpdated TaskEventType = "UPDATED"
+	TaskEventTypeDeleted TaskEventType = "DELETED"
+)
+
+var AllTaskEventType = []TaskEventType{
+	TaskEventTypeCreated,
+	TaskEventTypeUpdated,
+	TaskEventTypeDeleted,
+}
+
+func (e TaskEventType) IsValid() bool {
+	switch e {
+	case TaskEventTypeCreated, TaskEventTypeUpdated, TaskEventTypeDeleted:
+		return true
+	}
+	return false
+}
+
+func (e TaskEventType) String() string {
+	return string(e)
+}
+
+func (e *TaskEventType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskEventType", str)
+	}
+	return nil
+}
+
+func (e TaskEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// P0 is the most urgent.
+type TaskPriority string
+
+const (
+	TaskPriorityP0 TaskPriority = "P0"
+	TaskPriorityP1 TaskPriority = "P1"
+	TaskPriorityP2 TaskPriority = "P2"
+	TaskPriorityP3 TaskPriority = "P3"
+)
+
+var AllTaskPriority = []TaskPriority{
+	TaskPriorityP0,
+	TaskPriorityP1,
+	TaskPriorityP2,
+	TaskPriorityP3,
+}
+
+func (e TaskPriority) IsValid() bool {
+	switch e {
+	case TaskPriorityP0, TaskPriorityP1, TaskPriorityP2, TaskPriorityP3:
+		return true
+	}
+	return false
+}
+
+func (e TaskPriority) String() string {
+	return string(e)
+}
+
+func (e *TaskPriority) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskPriority(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskPriority", str)
+	}
+	return nil
+}
+
+func (e TaskPriority) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TaskStatus string
+
+const (
+	TaskStatusTodo       TaskStatus = "TODO"
+	TaskStatusInProgress TaskStatus = "IN_PROGRESS"
+	TaskStatusDone       TaskStatus = "DONE"
+)
+
+var AllTaskStatus = []TaskStatus{
+	TaskStatusTodo,
+	TaskStatusInProgress,
+	TaskStatusDone,
+}
+
+func (e TaskStatus) IsValid() bool {
+	switch e {
+	case TaskStatusTodo, TaskStatusInProgress, TaskStatusDone:
+		return true
+	}
+	return false
+}
+
+func (e TaskStatus) String() string {
+	return string(e)
+}
+
+func (e *TaskStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskStatus", str)
+	}
+	return nil
+}
+
+func (e TaskStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/graph/model"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+)
+
+// Resolver is the root of the GraphQL resolvers. Reads by ID go through the
+// per-request loaders so a query touching many tasks costs one repository
+// call per batch instead of one per task.
+type Resolver struct {
+	tasks *service.TaskService
+}
+
+func NewResolver(tasks *service.TaskService) *Resolver {
+	return &Resolver{tasks: tasks}
+}
+
+// changeTaskUser adds user, "me" for the caller, to the task's assignees or
+// watchers, or removes them.
+func (r *Resolver) changeTaskUser(ctx context.Context, id string, relation domain.TaskRelation, user string, add bool) (*model.Task, error) {
+	const msg = "Failed to update task"
+
+	taskID, err := parseID(id)
+	if err != nil {
+		return nil, toGQLError(ctx, err, msg)
+	}
+	if user == "me" {
+		if user = policy.User(ctx); user == "" {
+			return nil, toGQLError(ctx, fmt.Errorf(`%w: "me" requires an authenticated user`, errs.ErrUnauthenticated), msg)
+		}
+	}
+
+	var task *domain.Task
+	if add {
+		task, err = r.tasks.AddTaskUser(ctx, taskID, relation, user)
+	} else {
+		task, err = r.tasks.RemoveTaskUser(ctx, taskID, relation, user)
+	}
+	if err != nil {
+		return nil, toGQLError(ctx, err, msg)
+	}
+
+	return taskToModel(task), nil
+}
@@ -0,0 +1,216 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.49
+
+import (
+	"context"
+	"slices"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/graph/model"
+)
+
+// CreateTask is the resolver for the createTask field.
+func (r *mutationResolver) CreateTask(ctx context.Context, input model.CreateTaskInput) (*model.Task, error) {
+	task, err := createInputFromModel(input)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to create task")
+	}
+	if err := task.Validate(); err != nil {
+		return nil, toGQLError(ctx, err, "Failed to create task")
+	}
+
+	created, err := r.tasks.CreateTask(ctx, task)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to create task")
+	}
+
+	return taskToModel(created), nil
+}
+
+// UpdateTask is the resolver for the updateTask field.
+func (r *mutationResolver) UpdateTask(ctx context.Context, id string, input model.UpdateTaskInput) (*model.Task, error) {
+	taskID, err := parseID(id)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to update task")
+	}
+
+	update := updateInputFromModel(input)
+	if err := update.Validate(); err != nil {
+		return nil, toGQLError(ctx, err, "Failed to update task")
+	}
+
+	task, err := r.tasks.UpdateTask(ctx, taskID, update)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to update task")
+	}
+
+	return taskToModel(task), nil
+}
+
+// DeleteTask is the resolver for the deleteTask field.
+func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (string, error) {
+	taskID, err := parseID(id)
+	if err != nil {
+		return "", toGQLError(ctx, err, "Failed to delete task")
+	}
+
+	if err := r.tasks.DeleteTask(ctx, taskID); err != nil {
+		return "", toGQLError(ctx, err, "Failed to delete task")
+	}
+
+	return id, nil
+}
+
+// MoveTask is the resolver for the moveTask field.
+func (r *mutationResolver) MoveTask(ctx context.Context, id string, after *string, before *string) (*model.Task, error) {
+	taskID, err := parseID(id)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to move task")
+	}
+
+	var input domain.MoveTaskInput
+	if input.After, err = parseOptionalID(after); err != nil {
+		return nil, toGQLError(ctx, err, "Failed to move task")
+	}
+	if input.Before, err = parseOptionalID(before); err != nil {
+		return nil, toGQLError(ctx, err, "Failed to move task")
+	}
+
+	task, err := r.tasks.MoveTask(ctx, taskID, input)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to move task")
+	}
+
+	return taskToModel(task), nil
+}
+
+// AssignTask is the resolver for the assignTask field.
+func (r *mutationResolver) AssignTask(ctx context.Context, id string, user string) (*model.Task, error) {
+	return r.changeTaskUser(ctx, id, domain.TaskRelationAssignee, user, true)
+}
+
+// UnassignTask is the resolver for the unassignTask field.
+func (r *mutationResolver) UnassignTask(ctx context.Context, id string, user string) (*model.Task, error) {
+	return r.changeTaskUser(ctx, id, domain.TaskRelationAssignee, user, false)
+}
+
+// WatchTask is the resolver for the watchTask field.
+func (r *mutationResolver) WatchTask(ctx context.Context, id string, user string) (*model.Task, error) {
+	return r.changeTaskUser(ctx, id, domain.TaskRelationWatcher, user, true)
+}
+
+// UnwatchTask is the resolver for the unwatchTask field.
+func (r *mutationResolver) UnwatchTask(ctx context.Context, id string, user string) (*model.Task, error) {
+	return r.changeTaskUser(ctx, id, domain.TaskRelationWatcher, user, false)
+}
+
+// Task is the resolver for the task field.
+func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error) {
+	taskID, err := parseID(id)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to get task")
+	}
+
+	task, err := loadersFor(ctx).Task.Load(ctx, taskID)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to get task")
+	}
+	if task == nil {
+		return nil, nil
+	}
+
+	return taskToModel(task), nil
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *queryResolver) Tasks(ctx context.Context, filter *model.TaskFilter, first *int, after *string) (*model.TaskConnection, error) {
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to list tasks")
+	}
+
+	query, err := filterFromModel(filter)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to list tasks")
+	}
+	if after != nil {
+		if query.After, err = domain.ParseTaskCursor(*after); err != nil {
+			return nil, toGQLError(ctx, err, "Failed to list tasks")
+		}
+	}
+	// One extra row tells whether there is a next page.
+	query.Limit = limit + 1
+
+	tasks, err := r.tasks.FindTasks(ctx, query)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to list tasks")
+	}
+
+	conn := &model.TaskConnection{
+		Edges:    make([]*model.TaskEdge, 0, limit),
+		PageInfo: &model.PageInfo{HasNextPage: len(tasks) > limit},
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	loaders := loadersFor(ctx)
+	for _, task := range tasks {
+		loaders.Task.Prime(task.ID, task)
+		conn.Edges = append(conn.Edges, &model.TaskEdge{Cursor: task.Cursor().String(), Node: taskToModel(task)})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+
+	return conn, nil
+}
+
+// TaskChanged is the resolver for the taskChanged field.
+func (r *subscriptionResolver) TaskChanged(ctx context.Context, statuses []model.TaskStatus) (<-chan *model.TaskEvent, error) {
+	want := make([]domain.TaskStatus, len(statuses))
+	for i, st := range statuses {
+		want[i] = domain.TaskStatus(st)
+	}
+
+	events, err := r.tasks.Subscribe(ctx)
+	if err != nil {
+		return nil, toGQLError(ctx, err, "Failed to subscribe to tasks")
+	}
+
+	out := make(chan *model.TaskEvent)
+	go func() {
+		// Closing out ends the subscription, including when the broker drops
+		// a subscriber that fell behind.
+		defer close(out)
+		for event := range events {
+			// Deletions carry no status, so they are always reported.
+			if len(want) > 0 && event.Task != nil && !slices.Contains(want, event.Task.Status) {
+				continue
+			}
+			select {
+			case out <- eventToModel(event):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,36 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps service errors to gRPC status codes. Unexpected errors are
+// logged and reported as Internal without their details, as the REST API
+// does with 500s.
+func toStatus(ctx context.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errs.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errs.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errs.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, errs.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	zerolog.Ctx(ctx).Error().Err(err).Msg(msg)
+	return status.Error(codes.Internal, msg)
+}
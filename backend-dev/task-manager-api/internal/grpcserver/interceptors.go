@@ -0,0 +1,168 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/auth"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDMetadata mirrors the X-Request-ID header of the REST API.
+const requestIDMetadata = "x-request-id"
+
+// withRequestLogger stores a request-scoped logger on ctx, like the
+// RequestID middleware does for HTTP, and echoes the request ID back in the
+// response header.
+func withRequestLogger(ctx context.Context, log zerolog.Logger) (context.Context, zerolog.Logger) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadata); len(values) > 0 && len(values[0]) <= 128 {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+
+	logCtx := log.With().Str("request_id", id)
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logCtx = logCtx.Stringer("trace_id", sc.TraceID())
+	}
+	reqLog := logCtx.Logger()
+
+	return reqLog.WithContext(ctx), reqLog
+}
+
+// recovered turns a panic in a handler into an Internal error, like
+// gin.Recovery does for HTTP, so one bad call doesn't take the server down.
+func recovered(log zerolog.Logger, method string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Error().
+		Str("method", method).
+		Interface("panic", r).
+		Bytes("stack", debug.Stack()).
+		Msg("gRPC handler panicked")
+	*err = status.Error(codes.Internal, "Internal server error")
+}
+
+func unaryRecovery(log zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recovered(log, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+func streamRecovery(log zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recovered(log, info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
+
+func unaryLogger(log zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, reqLog := withRequestLogger(ctx, log)
+
+		resp, err := handler(ctx, req)
+
+		reqLog.Info().
+			Str("method", info.FullMethod).
+			Stringer("code", status.Code(err)).
+			Dur("latency", time.Since(start)).
+			Msg("gRPC request")
+
+		return resp, err
+	}
+}
+
+func streamLogger(log zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, reqLog := withRequestLogger(ss.Context(), log)
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+		reqLog.Info().
+			Str("method", info.FullMethod).
+			Stringer("code", status.Code(err)).
+			Dur("latency", time.Since(start)).
+			Msg("gRPC stream")
+
+		return err
+	}
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate identifies the caller from the "authorization" metadata, like
+// the Authenticate middleware does for HTTP. Calls without it go on
+// anonymously.
+func authenticate(ctx context.Context, a *auth.Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	token, ok := auth.BearerToken(values[0])
+	if !a.Checks(token) {
+		return ctx, nil
+	}
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Authorization must be a bearer token")
+	}
+	identity, err := a.Authenticate(ctx, token)
+	if err != nil {
+		if !errors.Is(err, errs.ErrUnauthenticated) {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to check bearer token")
+			return nil, status.Error(codes.Internal, "Failed to check token")
+		}
+		zerolog.Ctx(ctx).Debug().Err(err).Msg("Rejected bearer token")
+		return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+	}
+
+	return identity.Context(ctx), nil
+}
+
+func unaryAuth(a *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(a *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), a)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,57 @@
+package grpcserver
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryTurnsPanicsIntoInternal(t *testing.T) {
+	var logs bytes.Buffer
+	log := zerolog.New(&logs)
+
+	t.Run("unary", func(t *testing.T) {
+		logs.Reset()
+		info := &grpc.UnaryServerInfo{FullMethod: "/tasks.v1.TaskService/GetTask"}
+		_, err := unaryRecovery(log)(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+			panic("boom")
+		})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("err = %v, want Internal", err)
+		}
+		if !strings.Contains(logs.String(), `"panic":"boom"`) {
+			t.Errorf("panic not logged: %s", logs.String())
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		logs.Reset()
+		info := &grpc.StreamServerInfo{FullMethod: "/tasks.v1.TaskService/WatchTasks"}
+		err := streamRecovery(log)(nil, nil, info, func(srv any, ss grpc.ServerStream) error {
+			panic("boom")
+		})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("err = %v, want Internal", err)
+		}
+		if !strings.Contains(logs.String(), `"panic":"boom"`) {
+			t.Errorf("panic not logged: %s", logs.String())
+		}
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		want := status.Error(codes.NotFound, "Task not found")
+		info := &grpc.UnaryServerInfo{FullMethod: "/tasks.v1.TaskService/GetTask"}
+		resp, err := unaryRecovery(log)(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+			return "task", want
+		})
+		if resp != "task" || err != want {
+			t.Errorf("got %v, %v", resp, err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Package grpcserver serves the tasks.v1 gRPC API on top of the same
+// TaskService as the REST handlers.
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/api/proto/tasks/v1"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/auth"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server bundles the gRPC server with its health service, so shutdown can
+// report NOT_SERVING before connections are drained.
+type Server struct {
+	*grpc.Server
+	health *health.Server
+}
+
+// New returns a server for tasks. Callers authenticate with a bearer token
+// in the "authorization" metadata, verified by a.
+func New(tasks *service.TaskService, a *auth.Authenticator, log zerolog.Logger) *Server {
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryRecovery(log), unaryLogger(log), unaryAuth(a)),
+		grpc.ChainStreamInterceptor(streamRecovery(log), streamLogger(log), streamAuth(a)),
+	)
+
+	tasksv1.RegisterTaskServiceServer(srv, NewTaskServer(tasks))
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthSrv.SetServingStatus(tasksv1.TaskService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	reflection.Register(srv)
+
+	return &Server{Server: srv, health: healthSrv}
+}
+
+// Shutdown marks every service NOT_SERVING and waits for in-flight calls to
+// finish, or stops hard when ctx is done first. Open WatchTasks streams
+// only end when ctx expires.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
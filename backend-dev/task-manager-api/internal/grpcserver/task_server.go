@@ -0,0 +1,380 @@
+package grpcserver
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/api/proto/tasks/v1"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TaskServer struct {
+	tasksv1.UnimplementedTaskServiceServer
+	service *service.TaskService
+}
+
+func NewTaskServer(service *service.TaskService) *TaskServer {
+	return &TaskServer{service: service}
+}
+
+func (s *TaskServer) CreateTask(ctx context.Context, req *tasksv1.CreateTaskRequest) (*tasksv1.Task, error) {
+	projectID, err := parseProjectID(req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	input := domain.CreateTaskInput{
+		ProjectID:   projectID,
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		DueDate:     timeFromProto(req.GetDueDate()),
+	}
+	if req.GetStatus() != tasksv1.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		input.Status = statusFromProto(req.GetStatus())
+	}
+	if req.GetPriority() != tasksv1.TaskPriority_TASK_PRIORITY_UNSPECIFIED {
+		input.Priority = priorityFromProto(req.GetPriority())
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, toStatus(ctx, err, "Failed to create task")
+	}
+
+	task, err := s.service.CreateTask(ctx, input)
+	if err != nil {
+		return nil, toStatus(ctx, err, "Failed to create task")
+	}
+
+	return taskToProto(task), nil
+}
+
+func (s *TaskServer) GetTask(ctx context.Context, req *tasksv1.GetTaskRequest) (*tasksv1.Task, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.service.GetTask(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, err, "Failed to get task")
+	}
+
+	return taskToProto(task), nil
+}
+
+// ListTasks returns the tasks the caller may read, or only those of one
+// project, newest first.
+func (s *TaskServer) ListTasks(ctx context.Context, req *tasksv1.ListTasksRequest) (*tasksv1.ListTasksResponse, error) {
+	projectID, err := parseProjectID(req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*domain.Task
+	if projectID != nil {
+		tasks, err = s.service.FindTasks(ctx, domain.TaskFilter{ProjectID: projectID})
+	} else {
+		tasks, err = s.service.ListTasks(ctx)
+	}
+	if err != nil {
+		return nil, toStatus(ctx, err, "Failed to list tasks")
+	}
+
+	resp := &tasksv1.ListTasksResponse{Tasks: make([]*tasksv1.Task, len(tasks))}
+	for i, task := range tasks {
+		resp.Tasks[i] = taskToProto(task)
+	}
+	return resp, nil
+}
+
+func (s *TaskServer) UpdateTask(ctx context.Context, req *tasksv1.UpdateTaskRequest) (*tasksv1.Task, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	input := domain.UpdateTaskInput{
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     timeFromProto(req.GetDueDate()),
+	}
+	if req.GetStatus() != tasksv1.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		st := statusFromProto(req.GetStatus())
+		input.Status = &st
+	}
+	if req.GetPriority() != tasksv1.TaskPriority_TASK_PRIORITY_UNSPECIFIED {
+		p := priorityFromProto(req.GetPriority())
+		input.Priority = &p
+	}
+
+	if err := input.Validate(); err != nil {
+		return nil, toStatus(ctx, err, "Failed to update task")
+	}
+
+	task, err := s.service.UpdateTask(ctx, id, input)
+	if err != nil {
+		return nil, toStatus(ctx, err, "Failed to update task")
+	}
+
+	return taskToProto(task), nil
+}
+
+func (s *TaskServer) DeleteTask(ctx context.Context, req *tasksv1.DeleteTaskRequest) (*tasksv1.DeleteTaskResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteTask(ctx, id); err != nil {
+		return nil, toStatus(ctx, err, "Failed to delete task")
+	}
+
+	return &tasksv1.DeleteTaskResponse{}, nil
+}
+
+func (s *TaskServer) MoveTask(ctx context.Context, req *tasksv1.MoveTaskRequest) (*tasksv1.Task, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	var input domain.MoveTaskInput
+	if input.After, err = parseOptionalID(req.After); err != nil {
+		return nil, err
+	}
+	if input.Before, err = parseOptionalID(req.Before); err != nil {
+		return nil, err
+	}
+
+	task, err := s.service.MoveTask(ctx, id, input)
+	if err != nil {
+		return nil, toStatus(ctx, err, "Failed to move task")
+	}
+
+	return taskToProto(task), nil
+}
+
+func (s *TaskServer) AssignTask(ctx context.Context, req *tasksv1.TaskUserRequest) (*tasksv1.Task, error) {
+	return s.changeTaskUser(ctx, req, domain.TaskRelationAssignee, true)
+}
+
+func (s *TaskServer) UnassignTask(ctx context.Context, req *tasksv1.TaskUserRequest) (*tasksv1.Task, error) {
+	return s.changeTaskUser(ctx, req, domain.TaskRelationAssignee, false)
+}
+
+func (s *TaskServer) WatchTask(ctx context.Context, req *tasksv1.TaskUserRequest) (*tasksv1.Task, error) {
+	return s.changeTaskUser(ctx, req, domain.TaskRelationWatcher, true)
+}
+
+func (s *TaskServer) UnwatchTask(ctx context.Context, req *tasksv1.TaskUserRequest) (*tasksv1.Task, error) {
+	return s.changeTaskUser(ctx, req, domain.TaskRelationWatcher, false)
+}
+
+// changeTaskUser adds the user in req, "me" for the caller, to the task's
+// assignees or watchers, or removes them.
+func (s *TaskServer) changeTaskUser(ctx context.Context, req *tasksv1.TaskUserRequest, relation domain.TaskRelation, add bool) (*tasksv1.Task, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	user := req.GetUser()
+	if user == "me" {
+		if user = policy.User(ctx); user == "" {
+			return nil, status.Error(codes.Unauthenticated, `"me" requires an authenticated user`)
+		}
+	}
+
+	var task *domain.Task
+	if add {
+		task, err = s.service.AddTaskUser(ctx, id, relation, user)
+	} else {
+		task, err = s.service.RemoveTaskUser(ctx, id, relation, user)
+	}
+	if err != nil {
+		return nil, toStatus(ctx, err, "Failed to update task")
+	}
+
+	return taskToProto(task), nil
+}
+
+func (s *TaskServer) WatchTasks(req *tasksv1.WatchTasksRequest, stream tasksv1.TaskService_WatchTasksServer) error {
+	ctx := stream.Context()
+
+	statuses := make([]domain.TaskStatus, len(req.GetStatuses()))
+	for i, st := range req.GetStatuses() {
+		statuses[i] = statusFromProto(st)
+	}
+
+	events, err := s.service.Subscribe(ctx)
+	if err != nil {
+		return toStatus(ctx, err, "Failed to watch tasks")
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return status.FromContextError(ctx.Err()).Err()
+				}
+				return status.Error(codes.ResourceExhausted, "Watcher fell too far behind, reconnect")
+			}
+
+			// Deletions carry no status, so they are always reported.
+			if len(statuses) > 0 && event.Task != nil && !slices.Contains(statuses, event.Task.Status) {
+				continue
+			}
+
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func parseID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "Invalid task ID")
+	}
+	return id, nil
+}
+
+func parseOptionalID(raw *string) (*uuid.UUID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	id, err := parseID(*raw)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func parseProjectID(raw *string) (*uuid.UUID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*raw)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid project ID")
+	}
+	return &id, nil
+}
+
+func taskToProto(task *domain.Task) *tasksv1.Task {
+	pb := &tasksv1.Task{
+		Id:          task.ID.String(),
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      statusToProto(task.Status),
+		Priority:    priorityToProto(task.Priority),
+		Rank:        task.Rank,
+		Assignees:   task.Assignees,
+		Watchers:    task.Watchers,
+		CreatedAt:   timestamppb.New(task.CreatedAt),
+		UpdatedAt:   timestamppb.New(task.UpdatedAt),
+	}
+	if task.ProjectID != nil {
+		id := task.ProjectID.String()
+		pb.ProjectId = &id
+	}
+	if task.DueDate != nil {
+		pb.DueDate = timestamppb.New(*task.DueDate)
+	}
+	return pb
+}
+
+func eventToProto(event domain.TaskEvent) *tasksv1.TaskEvent {
+	pb := &tasksv1.TaskEvent{
+		TaskId: event.TaskID.String(),
+		Time:   timestamppb.New(event.Time),
+	}
+
+	switch event.Type {
+	case domain.TaskEventCreated:
+		pb.Type = tasksv1.TaskEvent_TYPE_CREATED
+	case domain.TaskEventUpdated:
+		pb.Type = tasksv1.TaskEvent_TYPE_UPDATED
+	case domain.TaskEventDeleted:
+		pb.Type = tasksv1.TaskEvent_TYPE_DELETED
+	}
+
+	if event.Task != nil {
+		pb.Task = taskToProto(event.Task)
+	}
+	return pb
+}
+
+func statusToProto(s domain.TaskStatus) tasksv1.TaskStatus {
+	switch s {
+	case domain.TaskStatusTodo:
+		return tasksv1.TaskStatus_TASK_STATUS_TODO
+	case domain.TaskStatusInProgress:
+		return tasksv1.TaskStatus_TASK_STATUS_IN_PROGRESS
+	case domain.TaskStatusDone:
+		return tasksv1.TaskStatus_TASK_STATUS_DONE
+	}
+	return tasksv1.TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+// statusFromProto returns an invalid status for unknown values, so
+// Validate rejects them.
+func statusFromProto(s tasksv1.TaskStatus) domain.TaskStatus {
+	switch s {
+	case tasksv1.TaskStatus_TASK_STATUS_TODO:
+		return domain.TaskStatusTodo
+	case tasksv1.TaskStatus_TASK_STATUS_IN_PROGRESS:
+		return domain.TaskStatusInProgress
+	case tasksv1.TaskStatus_TASK_STATUS_DONE:
+		return domain.TaskStatusDone
+	}
+	return domain.TaskStatus(s.String())
+}
+
+func priorityToProto(p domain.TaskPriority) tasksv1.TaskPriority {
+	switch p {
+	case domain.TaskPriorityP0:
+		return tasksv1.TaskPriority_TASK_PRIORITY_P0
+	case domain.TaskPriorityP1:
+		return tasksv1.TaskPriority_TASK_PRIORITY_P1
+	case domain.TaskPriorityP2:
+		return tasksv1.TaskPriority_TASK_PRIORITY_P2
+	case domain.TaskPriorityP3:
+		return tasksv1.TaskPriority_TASK_PRIORITY_P3
+	}
+	return tasksv1.TaskPriority_TASK_PRIORITY_UNSPECIFIED
+}
+
+// priorityFromProto returns an invalid priority for unknown values, so
+// Validate rejects them.
+func priorityFromProto(p tasksv1.TaskPriority) domain.TaskPriority {
+	switch p {
+	case tasksv1.TaskPriority_TASK_PRIORITY_P0:
+		return domain.TaskPriorityP0
+	case tasksv1.TaskPriority_TASK_PRIORITY_P1:
+		return domain.TaskPriorityP1
+	case tasksv1.TaskPriority_TASK_PRIORITY_P2:
+		return domain.TaskPriorityP2
+	case tasksv1.TaskPriority_TASK_PRIORITY_P3:
+		return domain.TaskPriorityP3
+	}
+	return domain.TaskPriority(p.String())
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+)
+
+type APITokenHandler struct {
+	service *service.APITokenService
+}
+
+func NewAPITokenHandler(service *service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{service: service}
+}
+
+// CreateToken issues an API token for the caller. The response is the only
+// place its secret ever appears.
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	var input domain.CreateAPITokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	token, secret, err := h.service.CreateToken(c.Request.Context(), input)
+	if err != nil {
+		h.fail(c, err, "Failed to create API token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":  token,
+		"secret": secret,
+	})
+}
+
+func (h *APITokenHandler) ListTokens(c *gin.Context) {
+	tokens, err := h.service.ListTokens(c.Request.Context())
+	if err != nil {
+		h.fail(c, err, "Failed to list API tokens")
+		return
+	}
+	if tokens == nil {
+		tokens = []*domain.APIToken{}
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *APITokenHandler) GetToken(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid API token ID"))
+		return
+	}
+
+	token, err := h.service.GetToken(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, "Failed to get API token")
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// RevokeToken revokes an API token; it stays in the listing as revoked.
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid API token ID"))
+		return
+	}
+
+	if err := h.service.RevokeToken(c.Request.Context(), id); err != nil {
+		h.fail(c, err, "Failed to revoke API token")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// fail responds to an error of the API token service; msg is the 500
+// message.
+func (h *APITokenHandler) fail(c *gin.Context, err error, msg string) {
+	if denied(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		c.JSON(http.StatusNotFound, errorBody(c, "API token not found"))
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errorBody(c, msg))
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+)
+
+type BoardHandler struct {
+	service *service.BoardService
+}
+
+func NewBoardHandler(service *service.BoardService) *BoardHandler {
+	return &BoardHandler{service: service}
+}
+
+func (h *BoardHandler) CreateBoard(c *gin.Context) {
+	var input domain.CreateBoardInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	board, err := h.service.CreateBoard(c.Request.Context(), input)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to create board"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, board)
+}
+
+func (h *BoardHandler) ListBoards(c *gin.Context) {
+	boards, err := h.service.ListBoards(c.Request.Context())
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to list boards"))
+		return
+	}
+	if boards == nil {
+		boards = []*domain.Board{}
+	}
+
+	c.JSON(http.StatusOK, boards)
+}
+
+// GetBoard returns the board with the first ?limit= tasks of every column.
+// The next page of a column is fetched with ?column= and the column's
+// next_cursor as ?cursor=.
+func (h *BoardHandler) GetBoard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid board ID"))
+		return
+	}
+
+	limit := defaultPageSize
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxPageSize {
+			c.JSON(http.StatusBadRequest, errorBody(c, fmt.Sprintf("limit must be between 1 and %d", maxPageSize)))
+			return
+		}
+		limit = n
+	}
+
+	column := c.Query("column")
+	var after *domain.TaskCursor
+	if raw := c.Query("cursor"); raw != "" {
+		if column == "" {
+			c.JSON(http.StatusBadRequest, errorBody(c, "cursor requires column"))
+			return
+		}
+		cursor, err := domain.ParseTaskCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorBody(c, "Invalid cursor"))
+			return
+		}
+		after = cursor
+	}
+
+	board, columns, err := h.service.BoardTasks(c.Request.Context(), id, column, after, limit)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "Board not found"))
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to get board"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"board":   board,
+		"columns": columns,
+	})
+}
+
+func (h *BoardHandler) UpdateBoard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid board ID"))
+		return
+	}
+
+	var input domain.UpdateBoardInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	board, err := h.service.UpdateBoard(c.Request.Context(), id, input)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "Board not found"))
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		case errors.Is(err, errs.ErrConflict):
+			c.JSON(http.StatusConflict, errorBody(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to update board"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+func (h *BoardHandler) DeleteBoard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid board ID"))
+		return
+	}
+
+	if err := h.service.DeleteBoard(c.Request.Context(), id); err != nil {
+		if denied(c, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorBody(c, "Board not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to delete board"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *BoardHandler) BoardVersions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid board ID"))
+		return
+	}
+
+	versions, err := h.service.BoardVersions(c.Request.Context(), id)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorBody(c, "Board not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to list board versions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
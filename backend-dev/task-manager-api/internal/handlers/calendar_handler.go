@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/ical"
+)
+
+const calendarProdID = "-//mhShohan//task-manager-api//EN"
+
+type CalendarHandler struct {
+	service *service.CalendarService
+}
+
+func NewCalendarHandler(service *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+func (h *CalendarHandler) CreateFeed(c *gin.Context) {
+	var input domain.CreateCalendarFeedInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	feed, token, err := h.service.CreateFeed(c.Request.Context(), input)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to create calendar feed"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":         feed.ID,
+		"owner":      feed.Owner,
+		"created_at": feed.CreatedAt,
+		"token":      token,
+		"url":        "/calendar/" + token + ".ics",
+	})
+}
+
+func (h *CalendarHandler) RevokeFeed(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid calendar feed ID"))
+		return
+	}
+
+	if err := h.service.RevokeFeed(c.Request.Context(), id); err != nil {
+		if denied(c, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorBody(c, "Calendar feed not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to revoke calendar feed"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Feed serves GET /calendar/:token.ics. Tasks become VTODOs by default, or
+// VEVENTs with ?component=event for calendar apps that ignore to-dos; in
+// that case the task status is carried in CATEGORIES. ?status= takes a
+// comma separated list of statuses to include.
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	var statuses []domain.TaskStatus
+	if raw := c.Query("status"); raw != "" {
+		for _, s := range strings.Split(raw, ",") {
+			status := domain.TaskStatus(strings.ToUpper(strings.TrimSpace(s)))
+			if !status.Valid() {
+				c.JSON(http.StatusBadRequest, errorBody(c, "Invalid status filter"))
+				return
+			}
+			statuses = append(statuses, status)
+		}
+	}
+
+	component := "VTODO"
+	switch c.DefaultQuery("component", "todo") {
+	case "todo":
+	case "event":
+		component = "VEVENT"
+	default:
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid component, use todo or event"))
+		return
+	}
+
+	tasks, err := h.service.FeedTasks(c.Request.Context(), token, statuses)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorBody(c, "Calendar feed not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to load calendar feed"))
+		return
+	}
+
+	body, lastModified := renderCalendar(tasks, component)
+	sum := sha256.Sum256(body)
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	c.Header("Cache-Control", "private, max-age=0, must-revalidate")
+
+	// ServeContent answers If-None-Match and If-Modified-Since with 304.
+	http.ServeContent(c.Writer, c.Request, "tasks.ics", lastModified, bytes.NewReader(body))
+}
+
+func renderCalendar(tasks []*domain.Task, component string) ([]byte, time.Time) {
+	var w ical.Writer
+	var lastModified time.Time
+
+	w.Begin("VCALENDAR")
+	w.Prop("VERSION", "2.0")
+	w.Prop("PRODID", calendarProdID)
+	w.Prop("CALSCALE", "GREGORIAN")
+	w.Text("X-WR-CALNAME", "Tasks")
+
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		if task.UpdatedAt.After(lastModified) {
+			lastModified = task.UpdatedAt
+		}
+
+		w.Begin(component)
+		w.Prop("UID", task.ID.String()+"@task-manager")
+		w.Time("DTSTAMP", task.UpdatedAt)
+		w.Time("LAST-MODIFIED", task.UpdatedAt)
+		w.Time("CREATED", task.CreatedAt)
+		w.Text("SUMMARY", task.Title)
+		if task.Description != "" {
+			w.Text("DESCRIPTION", task.Description)
+		}
+
+		if component == "VTODO" {
+			w.Time("DUE", *task.DueDate)
+			w.Prop("STATUS", todoStatus(task.Status))
+			if task.Status == domain.TaskStatusDone {
+				w.Prop("PERCENT-COMPLETE", "100")
+				w.Time("COMPLETED", task.UpdatedAt)
+			}
+		} else {
+			w.Time("DTSTART", *task.DueDate)
+			w.Prop("STATUS", "CONFIRMED")
+			w.Text("CATEGORIES", string(task.Status))
+		}
+
+		w.End(component)
+	}
+
+	w.End("VCALENDAR")
+
+	return w.Bytes(), lastModified
+}
+
+func todoStatus(status domain.TaskStatus) string {
+	switch status {
+	case domain.TaskStatusInProgress:
+		return "IN-PROCESS"
+	case domain.TaskStatusDone:
+		return "COMPLETED"
+	}
+	return "NEEDS-ACTION"
+}
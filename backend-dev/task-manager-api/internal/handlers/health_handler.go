@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/health"
+)
+
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{registry: registry}
+}
+
+// Livez answers 200 while the process is healthy and 503 once it needs a
+// restart, with the result of every check in the body.
+func (h *HealthHandler) Livez(c *gin.Context) {
+	writeHealthReport(c, h.registry.Liveness(c.Request.Context()))
+}
+
+// Readyz answers 200 while the instance can serve traffic. It fails when a
+// dependency is down and as soon as shutdown starts.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	writeHealthReport(c, h.registry.Readiness(c.Request.Context()))
+}
+
+func writeHealthReport(c *gin.Context, report health.Report) {
+	c.Header("Cache-Control", "no-store")
+
+	status := http.StatusOK
+	if !report.Passed() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
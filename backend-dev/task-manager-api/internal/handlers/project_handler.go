@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+)
+
+type ProjectHandler struct {
+	service *service.ProjectService
+}
+
+func NewProjectHandler(service *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{service: service}
+}
+
+// CreateProject creates a project with the caller as its owner.
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var input domain.CreateProjectInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	project, err := h.service.CreateProject(c.Request.Context(), input)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to create project"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+// ListProjects returns the projects the caller is a member of.
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	projects, err := h.service.ListProjects(c.Request.Context())
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to list projects"))
+		return
+	}
+	if projects == nil {
+		projects = []*domain.Project{}
+	}
+
+	c.JSON(http.StatusOK, projects)
+}
+
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid project ID"))
+		return
+	}
+
+	project, err := h.service.GetProject(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, "Failed to get project")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid project ID"))
+		return
+	}
+
+	var input domain.UpdateProjectInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	project, err := h.service.UpdateProject(c.Request.Context(), id, input)
+	if err != nil {
+		h.fail(c, err, "Failed to update project")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// DeleteProject deletes a project without tasks.
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid project ID"))
+		return
+	}
+
+	if err := h.service.DeleteProject(c.Request.Context(), id); err != nil {
+		h.fail(c, err, "Failed to delete project")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *ProjectHandler) ListMembers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid project ID"))
+		return
+	}
+
+	members, err := h.service.Members(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, "Failed to list project members")
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// SetMember gives the user in the path, "me" for the caller, the role in
+// the body.
+func (h *ProjectHandler) SetMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid project ID"))
+		return
+	}
+
+	var input domain.MembershipInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	user, ok := resolveUser(c, c.Param("user"))
+	if !ok {
+		return
+	}
+
+	membership, err := h.service.SetMember(c.Request.Context(), id, user, input)
+	if err != nil {
+		h.fail(c, err, "Failed to set project member")
+		return
+	}
+
+	c.JSON(http.StatusOK, membership)
+}
+
+// RemoveMember takes the user in the path, "me" for the caller, out of the
+// project.
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid project ID"))
+		return
+	}
+
+	user, ok := resolveUser(c, c.Param("user"))
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), id, user); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorBody(c, "Project member not found"))
+			return
+		}
+		h.fail(c, err, "Failed to remove project member")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// fail responds to an error of the project service; msg is the 500 message.
+func (h *ProjectHandler) fail(c *gin.Context, err error, msg string) {
+	if denied(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		c.JSON(http.StatusNotFound, errorBody(c, "Project not found"))
+	case errors.Is(err, errs.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+	case errors.Is(err, errs.ErrConflict):
+		c.JSON(http.StatusConflict, errorBody(c, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, errorBody(c, msg))
+	}
+}
@@ -2,13 +2,24 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/middlewares"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/jsonpatch"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
 )
 
 type TaskHandler struct {
@@ -22,13 +33,20 @@ func NewTaskHandler(service *service.TaskService) *TaskHandler {
 func (h *TaskHandler) CreateTask(c *gin.Context) {
 	var input domain.CreateTaskInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
 		return
 	}
 
 	task, err := h.service.CreateTask(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
+		if denied(c, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to create task"))
 		return
 	}
 
@@ -38,75 +56,502 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
 		return
 	}
 
 	task, err := h.service.GetTask(c.Request.Context(), id)
 	if err != nil {
+		if denied(c, err) {
+			return
+		}
 		if errors.Is(err, errs.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			c.JSON(http.StatusNotFound, errorBody(c, "Task not found"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get task"})
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to get task"))
 		return
 	}
 
 	c.JSON(http.StatusOK, task)
 }
 
+// ListTasks returns every task, or one page of them when ?limit= or
+// ?cursor= is given, newest first or, with ?sort=rank, in rank order. The
+// next page is linked in a Link header with rel="next". ?assignee= keeps the
+// tasks assigned to a user, "me" for the caller, and ?unassigned=true those
+// assigned to nobody. ?project= keeps the tasks of one project. Only the
+// tasks the caller may read are listed.
 func (h *TaskHandler) ListTasks(c *gin.Context) {
-	tasks, err := h.service.ListTasks(c.Request.Context())
+	filter, ok := listFilter(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("limit") != "" || c.Query("cursor") != "" {
+		h.listTaskPage(c, filter)
+		return
+	}
+
+	tasks, err := h.service.FindTasks(c.Request.Context(), filter)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to list tasks"))
+		return
+	}
+	if tasks == nil {
+		tasks = []*domain.Task{}
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
+
+// listFilter reads the filters of ListTasks. It reports false after
+// responding when they are invalid.
+func listFilter(c *gin.Context) (domain.TaskFilter, bool) {
+	var filter domain.TaskFilter
+
+	switch c.Query("sort") {
+	case "", "created_at":
+	case "rank":
+		filter.RankOrder = true
+	default:
+		c.JSON(http.StatusBadRequest, errorBody(c, "sort must be created_at or rank"))
+		return filter, false
+	}
+
+	if raw := c.Query("project"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorBody(c, "Invalid project ID"))
+			return filter, false
+		}
+		filter.ProjectID = &id
+	}
+
+	if raw := c.Query("unassigned"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorBody(c, "Invalid unassigned value"))
+			return filter, false
+		}
+		filter.Unassigned = v
+	}
+
+	if raw := c.Query("assignee"); raw != "" {
+		if filter.Unassigned {
+			c.JSON(http.StatusBadRequest, errorBody(c, "assignee and unassigned cannot be combined"))
+			return filter, false
+		}
+		user, ok := resolveUser(c, raw)
+		if !ok {
+			return filter, false
+		}
+		filter.Assignee = user
+	}
+
+	return filter, true
+}
+
+func (h *TaskHandler) listTaskPage(c *gin.Context, filter domain.TaskFilter) {
+	limit := defaultPageSize
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxPageSize {
+			c.JSON(http.StatusBadRequest, errorBody(c, fmt.Sprintf("limit must be between 1 and %d", maxPageSize)))
+			return
+		}
+		limit = n
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := domain.ParseTaskCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorBody(c, "Invalid cursor"))
+			return
+		}
+		filter.After = cursor
+	}
+	// One extra row tells whether there is a next page.
+	filter.Limit = limit + 1
+
+	tasks, err := h.service.FindTasks(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tasks"})
+		if denied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to list tasks"))
 		return
 	}
 
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+		// The next page keeps the filters of this one.
+		next := c.Request.URL.Query()
+		next.Set("limit", strconv.Itoa(limit))
+		next.Set("cursor", tasks[limit-1].Cursor().String())
+		c.Header("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, c.Request.URL.Path, next.Encode()))
+	}
+	if tasks == nil {
+		tasks = []*domain.Task{}
+	}
+
 	c.JSON(http.StatusOK, tasks)
 }
 
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
 		return
 	}
 
 	var input domain.UpdateTaskInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
 		return
 	}
 
 	task, err := h.service.UpdateTask(c.Request.Context(), id, input)
 	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "Task not found"))
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		case errors.Is(err, errs.ErrConflict):
+			c.JSON(http.StatusConflict, errorBody(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to update task"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// MoveTask places the task right after or before another task, or between
+// two, in the manual ordering.
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
+		return
+	}
+
+	var input domain.MoveTaskInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	task, err := h.service.MoveTask(c.Request.Context(), id, input)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "Task not found"))
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to move task"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// AssignTask adds the user in the body, "me" for the caller, to the task's
+// assignees.
+func (h *TaskHandler) AssignTask(c *gin.Context) {
+	h.addTaskUser(c, domain.TaskRelationAssignee)
+}
+
+// UnassignTask removes the user in the path from the task's assignees.
+func (h *TaskHandler) UnassignTask(c *gin.Context) {
+	h.removeTaskUser(c, domain.TaskRelationAssignee)
+}
+
+// WatchTask adds the user in the body, "me" for the caller, to the task's
+// watchers.
+func (h *TaskHandler) WatchTask(c *gin.Context) {
+	h.addTaskUser(c, domain.TaskRelationWatcher)
+}
+
+// UnwatchTask removes the user in the path from the task's watchers.
+func (h *TaskHandler) UnwatchTask(c *gin.Context) {
+	h.removeTaskUser(c, domain.TaskRelationWatcher)
+}
+
+// TaskHistory returns the changes made to the task's assignees and
+// watchers, oldest first.
+func (h *TaskHandler) TaskHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
+		return
+	}
+
+	entries, err := h.service.TaskHistory(c.Request.Context(), id)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
 		if errors.Is(err, errs.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			c.JSON(http.StatusNotFound, errorBody(c, "Task not found"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to get task history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *TaskHandler) addTaskUser(c *gin.Context, relation domain.TaskRelation) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
+		return
+	}
+
+	var input domain.TaskUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	user, ok := resolveUser(c, input.User)
+	if !ok {
+		return
+	}
+
+	task, err := h.service.AddTaskUser(c.Request.Context(), id, relation, user)
+	h.respondTaskUser(c, task, err)
+}
+
+func (h *TaskHandler) removeTaskUser(c *gin.Context, relation domain.TaskRelation) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
+		return
+	}
+
+	user, ok := resolveUser(c, c.Param("user"))
+	if !ok {
+		return
+	}
+
+	task, err := h.service.RemoveTaskUser(c.Request.Context(), id, relation, user)
+	h.respondTaskUser(c, task, err)
+}
+
+func (h *TaskHandler) respondTaskUser(c *gin.Context, task *domain.Task, err error) {
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "Task not found"))
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to update task"))
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, task)
 }
 
+// resolveUser replaces "me" with the authenticated caller. It reports
+// false after responding when there is no caller to stand in for.
+func resolveUser(c *gin.Context, user string) (string, bool) {
+	if user != "me" {
+		return user, true
+	}
+	caller := c.GetString(middleware.UserIDKey)
+	if caller == "" {
+		c.JSON(http.StatusUnauthorized, errorBody(c, `"me" requires an authenticated user`))
+		return "", false
+	}
+	return caller, true
+}
+
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
 		return
 	}
 
 	err = h.service.DeleteTask(c.Request.Context(), id)
 	if err != nil {
+		if denied(c, err) {
+			return
+		}
 		if errors.Is(err, errs.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+			c.JSON(http.StatusNotFound, errorBody(c, "Task not found"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to delete task"))
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// BatchTasks serves POST /api/v1/tasks:batch. Gin has no literal ':' in
+// static paths, so the route is registered as "/tasks" followed by a
+// parameter and anything other than ":batch" is rejected here.
+func (h *TaskHandler) BatchTasks(c *gin.Context) {
+	if c.Param("action") != ":batch" {
+		c.JSON(http.StatusNotFound, errorBody(c, "Not found"))
+		return
+	}
+
+	var input domain.BatchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	result, err := h.service.BatchTasks(c.Request.Context(), input)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to process batch"))
+		return
+	}
+
+	for i := range result.Results {
+		if err := result.Results[i].Err; err != nil {
+			result.Results[i].Error = batchErrorMessage(err)
+		}
+	}
+
+	status := http.StatusOK
+	if !result.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, result)
+}
+
+func batchErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		return "Task not found"
+	case errors.Is(err, errs.ErrInvalidInput), errors.Is(err, errs.ErrConflict),
+		errors.Is(err, errs.ErrUnauthenticated), errors.Is(err, errs.ErrForbidden):
+		return err.Error()
+	}
+	return "Internal error"
+}
+
+// PatchTask applies an RFC 7396 merge patch or an RFC 6902 JSON patch,
+// depending on the request Content-Type. ?override_wip_limit=true lets a
+// status change take a board column over its WIP limit, as the field of
+// the same name does for PUT.
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid task ID"))
+		return
+	}
+
+	override := false
+	if raw := c.Query("override_wip_limit"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorBody(c, "Invalid override_wip_limit value"))
+			return
+		}
+		override = v
+	}
+
+	var apply func(doc, patch []byte) ([]byte, error)
+	switch c.ContentType() {
+	case "application/merge-patch+json":
+		apply = jsonpatch.MergePatch
+	case "application/json-patch+json":
+		apply = jsonpatch.Apply
+	default:
+		c.Header("Accept-Patch", "application/merge-patch+json, application/json-patch+json")
+		c.JSON(http.StatusUnsupportedMediaType, errorBody(c, "Unsupported patch format"))
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	task, err := h.service.PatchTask(c.Request.Context(), id, func(doc []byte) ([]byte, error) {
+		return apply(doc, patch)
+	}, override)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "Task not found"))
+		case errors.Is(err, jsonpatch.ErrTestFailed), errors.Is(err, errs.ErrConflict):
+			c.JSON(http.StatusConflict, errorBody(c, err.Error()))
+		case errors.Is(err, jsonpatch.ErrInvalidPatch):
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		case errors.Is(err, jsonpatch.ErrPathNotFound), errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusUnprocessableEntity, errorBody(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to patch task"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+func errorBody(c *gin.Context, message string) gin.H {
+	return middleware.ErrorBody(c, message)
+}
+
+// denied responds 401 or 403 when err comes from the policy, naming the
+// missing permission or token scope in a 403. It reports whether it
+// responded.
+func denied(c *gin.Context, err error) bool {
+	var missing *policy.DeniedError
+	var unscoped *policy.ScopeError
+	switch {
+	case errors.As(err, &missing):
+		body := errorBody(c, err.Error())
+		body["permission"] = missing.Permission
+		c.JSON(http.StatusForbidden, body)
+	case errors.As(err, &unscoped):
+		body := errorBody(c, err.Error())
+		body["scope"] = unscoped.Scope
+		c.JSON(http.StatusForbidden, body)
+	case errors.Is(err, errs.ErrForbidden):
+		c.JSON(http.StatusForbidden, errorBody(c, err.Error()))
+	case errors.Is(err, errs.ErrUnauthenticated):
+		c.JSON(http.StatusUnauthorized, errorBody(c, err.Error()))
+	default:
+		return false
+	}
+	return true
+}
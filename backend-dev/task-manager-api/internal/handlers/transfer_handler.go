@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+const (
+	formatCSV   = "csv"
+	formatJSONL = "jsonl"
+
+	// maxImportBytes bounds the size of an import request body.
+	maxImportBytes = 32 << 20
+
+	// exportFlushEvery controls how many rows are buffered before they are
+	// flushed to the client.
+	exportFlushEvery = 100
+)
+
+var csvColumns = []string{"id", "project_id", "title", "description", "status", "priority", "rank", "due_date", "created_at", "updated_at"}
+
+// ExportTasks serves GET /api/v1/tasks/export?format=csv|jsonl and streams
+// the tasks row by row.
+func (h *TaskHandler) ExportTasks(c *gin.Context) {
+	format := c.DefaultQuery("format", formatCSV)
+
+	var (
+		writeRow func(task *domain.Task) error
+		flush    func() error
+	)
+
+	switch format {
+	case formatCSV:
+		w := csv.NewWriter(c.Writer)
+		_ = w.Write(csvColumns)
+		writeRow = func(task *domain.Task) error { return w.Write(taskToCSV(task)) }
+		flush = func() error { w.Flush(); return w.Error() }
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+	case formatJSONL:
+		w := bufio.NewWriter(c.Writer)
+		enc := json.NewEncoder(w)
+		writeRow = func(task *domain.Task) error { return enc.Encode(task) }
+		flush = w.Flush
+		c.Header("Content-Type", "application/x-ndjson")
+	default:
+		c.JSON(http.StatusBadRequest, errorBody(c, "Unsupported format, use csv or jsonl"))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+	c.Status(http.StatusOK)
+
+	rows := 0
+	err := h.service.ExportTasks(c.Request.Context(), func(task *domain.Task) error {
+		if err := writeRow(task); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	if err != nil {
+		// Once rows have reached the client the status can't be changed
+		// any more; all we can do is cut the stream short.
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			if denied(c, err) {
+				return
+			}
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to export tasks"))
+			return
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	_ = flush()
+	c.Writer.Flush()
+}
+
+// ImportTasks serves POST /api/v1/tasks/import. The body is a CSV file with
+// a header row or a JSON Lines file, selected with ?format= or the request
+// Content-Type. With ?dry_run=true the rows are only validated.
+func (h *TaskHandler) ImportTasks(c *gin.Context) {
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorBody(c, "Invalid dry_run value"))
+			return
+		}
+		dryRun = v
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		switch c.ContentType() {
+		case "text/csv":
+			format = formatCSV
+		case "application/x-ndjson", "application/jsonl":
+			format = formatJSONL
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, errorBody(c, "Import file too large"))
+			return
+		}
+		c.JSON(http.StatusBadRequest, errorBody(c, "Failed to read import file"))
+		return
+	}
+
+	var rows []domain.ImportRow
+	switch format {
+	case formatCSV:
+		rows, err = parseCSVImport(body)
+	case formatJSONL:
+		rows, err = parseJSONLImport(body)
+	default:
+		c.JSON(http.StatusBadRequest, errorBody(c, "Unsupported format, use csv or jsonl"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	result, err := h.service.ImportTasks(c.Request.Context(), rows, dryRun)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to import tasks"))
+		return
+	}
+
+	switch {
+	case len(result.Errors) > 0:
+		c.JSON(http.StatusUnprocessableEntity, result)
+	case dryRun:
+		c.JSON(http.StatusOK, result)
+	default:
+		c.JSON(http.StatusCreated, result)
+	}
+}
+
+func taskToCSV(task *domain.Task) []string {
+	dueDate := ""
+	if task.DueDate != nil {
+		dueDate = task.DueDate.Format(time.RFC3339)
+	}
+
+	projectID := ""
+	if task.ProjectID != nil {
+		projectID = task.ProjectID.String()
+	}
+
+	return []string{
+		task.ID.String(),
+		projectID,
+		task.Title,
+		task.Description,
+		string(task.Status),
+		string(task.Priority),
+		task.Rank,
+		dueDate,
+		task.CreatedAt.Format(time.RFC3339),
+		task.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// parseCSVImport maps each record onto a CreateTaskInput by header name.
+// The id, rank, created_at and updated_at columns are accepted so that an
+// export can be imported again, but they are ignored; imported tasks are
+// ranked in file order.
+func parseCSVImport(body []byte) ([]domain.ImportRow, error) {
+	r := csv.NewReader(bytes.NewReader(body))
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "project_id", "title", "description", "status", "priority", "due_date":
+			columns[name] = i
+		case "id", "rank", "created_at", "updated_at":
+		default:
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("CSV header must contain a title column")
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []domain.ImportRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, domain.ImportRow{
+				Line: parseErr.Line,
+				Err:  fmt.Errorf("%w: %v", errs.ErrInvalidInput, parseErr.Err),
+			})
+			continue
+		}
+
+		line, _ := r.FieldPos(0)
+		row := domain.ImportRow{Line: line}
+		row.Input = domain.CreateTaskInput{
+			Title:       field(record, "title"),
+			Description: field(record, "description"),
+			Status:      domain.TaskStatus(field(record, "status")),
+			Priority:    domain.TaskPriority(field(record, "priority")),
+		}
+
+		if raw := field(record, "project_id"); raw != "" {
+			projectID, err := uuid.Parse(raw)
+			if err != nil {
+				row.Err = fmt.Errorf("%w: project_id must be a UUID", errs.ErrInvalidInput)
+			} else {
+				row.Input.ProjectID = &projectID
+			}
+		}
+
+		if raw := field(record, "due_date"); raw != "" {
+			dueDate, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				row.Err = fmt.Errorf("%w: due_date must be RFC 3339", errs.ErrInvalidInput)
+			} else {
+				row.Input.DueDate = &dueDate
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseJSONLImport(body []byte) ([]domain.ImportRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), maxImportBytes)
+
+	var rows []domain.ImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		row := domain.ImportRow{Line: line}
+		if err := json.Unmarshal(text, &row.Input); err != nil {
+			row.Err = fmt.Errorf("%w: %v", errs.ErrInvalidInput, err)
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+)
+
+type ViewHandler struct {
+	service *service.ViewService
+}
+
+func NewViewHandler(service *service.ViewService) *ViewHandler {
+	return &ViewHandler{service: service}
+}
+
+func (h *ViewHandler) CreateView(c *gin.Context) {
+	var input domain.CreateViewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	view, err := h.service.CreateView(c.Request.Context(), input)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to create view"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, view)
+}
+
+// ListViews returns the views saved by ?owner= and those shared with
+// ?team=, or every view when neither is given.
+func (h *ViewHandler) ListViews(c *gin.Context) {
+	views, err := h.service.ListViews(c.Request.Context(), c.Query("owner"), c.Query("team"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to list views"))
+		return
+	}
+	if views == nil {
+		views = []*domain.View{}
+	}
+
+	c.JSON(http.StatusOK, views)
+}
+
+func (h *ViewHandler) GetView(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid view ID"))
+		return
+	}
+
+	view, err := h.service.GetView(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorBody(c, "View not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to get view"))
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *ViewHandler) UpdateView(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid view ID"))
+		return
+	}
+
+	var input domain.UpdateViewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		return
+	}
+
+	view, err := h.service.UpdateView(c.Request.Context(), id, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "View not found"))
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, errorBody(c, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to update view"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+func (h *ViewHandler) DeleteView(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid view ID"))
+		return
+	}
+
+	if err := h.service.DeleteView(c.Request.Context(), id); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, errorBody(c, "View not found"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to delete view"))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ViewTasks runs the view. Each task is reduced to the view's columns, in
+// the order given by the view returned with them; a view without columns
+// returns whole tasks.
+func (h *ViewHandler) ViewTasks(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(c, "Invalid view ID"))
+		return
+	}
+
+	view, tasks, err := h.service.RunView(c.Request.Context(), id)
+	if err != nil {
+		if denied(c, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, errs.ErrNotFound):
+			c.JSON(http.StatusNotFound, errorBody(c, "View not found"))
+		case errors.Is(err, errs.ErrInvalidInput):
+			c.JSON(http.StatusUnprocessableEntity, errorBody(c, "View needs updating: "+err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, errorBody(c, "Failed to run view"))
+		}
+		return
+	}
+
+	rows := make([]any, len(tasks))
+	for i, task := range tasks {
+		rows[i] = view.Row(task)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"view":  view,
+		"tasks": rows,
+	})
+}
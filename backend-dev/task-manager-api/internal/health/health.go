@@ -0,0 +1,201 @@
+// Package health runs the liveness and readiness checks behind /livez and
+// /readyz.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// errShuttingDown fails readiness once Shutdown has been called.
+var errShuttingDown = errors.New("shutting down")
+
+// CheckFunc reports a problem by returning an error. It must honour ctx,
+// which carries the check's timeout.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the response body of /livez and /readyz.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Passed reports whether every check passed.
+func (r Report) Passed() bool {
+	return r.Status == StatusPass
+}
+
+type check struct {
+	name    string
+	timeout time.Duration
+	fn      CheckFunc
+
+	// mu serializes runs, so concurrent probes share one result instead of
+	// piling up on a slow dependency.
+	mu     sync.Mutex
+	result CheckResult
+}
+
+// Registry holds the liveness and readiness checks. Results are cached for
+// cacheTTL so frequent probes from several load balancers don't hammer the
+// dependencies.
+type Registry struct {
+	cacheTTL     time.Duration
+	liveness     []*check
+	readiness    []*check
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry(cacheTTL time.Duration) *Registry {
+	return &Registry{cacheTTL: cacheTTL}
+}
+
+// AddLiveness registers a check that, when failing, means the process
+// should be restarted. Keep these to things a restart fixes.
+func (r *Registry) AddLiveness(name string, timeout time.Duration, fn CheckFunc) {
+	r.liveness = append(r.liveness, &check{name: name, timeout: timeout, fn: fn})
+}
+
+// AddReadiness registers a check that, when failing, means the instance
+// should not receive traffic.
+func (r *Registry) AddReadiness(name string, timeout time.Duration, fn CheckFunc) {
+	r.readiness = append(r.readiness, &check{name: name, timeout: timeout, fn: fn})
+}
+
+// Shutdown makes readiness fail from now on so load balancers stop sending
+// new requests while in-flight ones finish.
+func (r *Registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, r.liveness)
+}
+
+func (r *Registry) Readiness(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{
+			Status: StatusFail,
+			Checks: map[string]CheckResult{
+				"shutdown": {Status: StatusFail, Error: errShuttingDown.Error(), Duration: "0s", CheckedAt: time.Now()},
+			},
+		}
+	}
+	return r.run(ctx, r.readiness)
+}
+
+func (r *Registry) run(ctx context.Context, checks []*check) Report {
+	report := Report{Status: StatusPass, Checks: make(map[string]CheckResult, len(checks))}
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, r.cacheTTL)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status != StatusPass {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
+
+func (c *check) run(ctx context.Context, cacheTTL time.Duration) CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.result.CheckedAt.IsZero() && time.Since(c.result.CheckedAt) < cacheTTL {
+		return c.result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runCheck(ctx, c.fn)
+
+	c.result = CheckResult{
+		Status:    StatusPass,
+		Duration:  time.Since(start).Round(time.Microsecond).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		c.result.Status = StatusFail
+		c.result.Error = err.Error()
+	}
+
+	return c.result
+}
+
+// runCheck gives up when ctx expires even if fn ignores it, and turns a
+// panicking check into a failure.
+func runCheck(ctx context.Context, fn CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Heartbeat tracks whether a background worker is still making progress.
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+func NewHeartbeat() *Heartbeat {
+	h := &Heartbeat{}
+	h.Beat()
+	return h
+}
+
+// Beat records that the worker is alive.
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Check fails when the last beat is older than maxAge.
+func (h *Heartbeat) Check(maxAge time.Duration) CheckFunc {
+	return func(ctx context.Context) error {
+		age := time.Since(time.Unix(0, h.last.Load()))
+		if age > maxAge {
+			return fmt.Errorf("last heartbeat %s ago", age.Round(time.Second))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+// Package metrics defines the Prometheus metrics exposed on the admin port.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+)
+
+const namespace = "taskmanager"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	RequestsTotal    *prometheus.CounterVec
+	RequestDuration  *prometheus.HistogramVec
+	RequestsInFlight prometheus.Gauge
+
+	TasksByStatus *prometheus.GaugeVec
+	TasksOverdue  prometheus.Gauge
+
+	CacheLookups *prometheus.CounterVec
+}
+
+// New registers the HTTP, business, Go runtime and process metrics, plus the
+// connection pool statistics of db unless it is nil.
+func New(db *sql.DB) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		RequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+
+		RequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		RequestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served.",
+		}),
+
+		TasksByStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tasks",
+			Help:      "Number of tasks by status.",
+		}, []string{"status"}),
+
+		TasksOverdue: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "tasks_overdue",
+			Help:      "Number of tasks past their due date that are not done.",
+		}),
+
+		CacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "task_cache_lookups_total",
+			Help:      "Task cache lookups by result: hit, miss or error.",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		m.RequestsTotal,
+		m.RequestDuration,
+		m.RequestsInFlight,
+		m.TasksByStatus,
+		m.TasksOverdue,
+		m.CacheLookups,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	if db != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db, "taskdb"))
+	}
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RefreshTaskStats updates the business gauges from load every interval
+// until ctx is cancelled. Counting tasks is too expensive to do on every
+// scrape, so the gauges may lag by up to one interval.
+func (m *Metrics) RefreshTaskStats(ctx context.Context, interval time.Duration, load func(ctx context.Context) (*domain.TaskStats, error), log zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stats, err := load(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to refresh task metrics")
+		} else {
+			for _, status := range domain.TaskStatuses {
+				m.TasksByStatus.WithLabelValues(string(status)).Set(float64(stats.ByStatus[status]))
+			}
+			m.TasksOverdue.Set(float64(stats.Overdue))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
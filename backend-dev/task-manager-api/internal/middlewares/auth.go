@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/auth"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/rs/zerolog"
+)
+
+// Authenticate identifies the caller from an "Authorization: Bearer" header
+// carrying a JWT or an API token. The user is stored under UserIDKey, the
+// ID of an API token under APITokenIDKey, and both go on the request
+// context, where the services find it with policy.User,
+// together with the scopes of an API token. Requests without the header go
+// on anonymously; a token that doesn't verify gets 401. While JWTs aren't
+// configured a header without an API token is ignored.
+func Authenticate(a *auth.Authenticator, log zerolog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, ok := auth.BearerToken(header)
+		if header == "" || !a.Checks(token) {
+			c.Next()
+			return
+		}
+
+		if !ok {
+			unauthenticated(c, "Authorization must be a bearer token")
+			return
+		}
+
+		identity, err := a.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			if !errors.Is(err, errs.ErrUnauthenticated) {
+				requestLogger(c, log).Error().Err(err).Msg("Failed to check bearer token")
+				c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorBody(c, "Failed to check token"))
+				return
+			}
+			requestLogger(c, log).Debug().Err(err).Msg("Rejected bearer token")
+			unauthenticated(c, "Invalid or expired token")
+			return
+		}
+
+		c.Set(UserIDKey, identity.User)
+		if identity.TokenID != uuid.Nil {
+			c.Set(APITokenIDKey, identity.TokenID.String())
+		}
+		c.Request = c.Request.WithContext(identity.Context(c.Request.Context()))
+		c.Next()
+	}
+}
+
+// RequireUser rejects anonymous requests to the routes policy.Routes
+// doesn't mark public, while p is enforced. Unknown paths are left to the
+// router's 404.
+func RequireUser(p *policy.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !p.Enforced() || c.FullPath() == "" || policy.Routes[c.Request.Method+" "+c.FullPath()].Public {
+			c.Next()
+			return
+		}
+
+		if c.GetString(UserIDKey) == "" {
+			unauthenticated(c, "Sign in to use this API")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests made with an API token whose scopes don't
+// include the one policy.Routes gives the route, enforced or not.
+func RequireScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := policy.Routes[c.Request.Method+" "+c.FullPath()]
+		if !ok || access.Public {
+			c.Next()
+			return
+		}
+
+		if err := policy.RequireScope(c.Request.Context(), access.TokenScope()); err != nil {
+			body := ErrorBody(c, err.Error())
+			body["scope"] = access.TokenScope()
+			c.AbortWithStatusJSON(http.StatusForbidden, body)
+			return
+		}
+		c.Next()
+	}
+}
+
+func unauthenticated(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="task-manager-api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorBody(c, message))
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotencyTTL is how long a stored response is replayed.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyLockTimeout is how long an unfinished request keeps its key
+	// locked before a retry may take over.
+	idempotencyLockTimeout  = time.Minute
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to
+// retry. The first request with a key is processed normally and its response
+// stored; identical retries get the stored response back. Reusing a key with
+// a different request is rejected with 422, and a retry that arrives while
+// the first request is still running gets 409. Keys are scoped to the
+// signed-in user, so one user's key is never replayed to another; anonymous
+// callers share a scope of their own. The body of a request with a key is
+// read into memory to fingerprint it, so bodies over maxBodyBytes are
+// rejected with 413.
+func Idempotency(repo repository.IdempotencyRepository, maxBodyBytes int64, log zerolog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorBody(c, "Idempotency-Key is too long"))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorBody(c, "Request body too large"))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorBody(c, "Failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		now := time.Now()
+		record := &domain.IdempotencyRecord{
+			Key:         idempotencyStoreKey(policy.User(ctx), key),
+			Fingerprint: requestFingerprint(c.Request, body),
+			LockedAt:    now,
+			CreatedAt:   now,
+		}
+
+		acquired, err := repo.Acquire(ctx, record, now.Add(-idempotencyTTL), now.Add(-idempotencyLockTimeout))
+		if err != nil {
+			requestLogger(c, log).Error().Err(err).Msg("Failed to acquire idempotency key")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorBody(c, "Internal error"))
+			return
+		}
+
+		if !acquired {
+			replayIdempotent(c, repo, record, log)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Store the outcome even if the client has gone away in the
+		// meantime; that is exactly the case a retry needs it for.
+		storeCtx := context.WithoutCancel(ctx)
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			// Server errors are not final, let the client retry for real.
+			if err := repo.Release(storeCtx, record.Key); err != nil {
+				requestLogger(c, log).Error().Err(err).Msg("Failed to release idempotency key")
+			}
+			return
+		}
+
+		record.StatusCode = recorder.Status()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.Bytes()
+
+		if err := repo.Complete(storeCtx, record); err != nil {
+			requestLogger(c, log).Error().Err(err).Msg("Failed to store idempotent response")
+		}
+	}
+}
+
+func replayIdempotent(c *gin.Context, repo repository.IdempotencyRepository, record *domain.IdempotencyRecord, log zerolog.Logger) {
+	stored, err := repo.Get(c.Request.Context(), record.Key)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			// The other request failed and released the key just now.
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusConflict, ErrorBody(c, "A request with this Idempotency-Key is in progress"))
+			return
+		}
+		requestLogger(c, log).Error().Err(err).Msg("Failed to load idempotency key")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorBody(c, "Internal error"))
+		return
+	}
+
+	if stored.Fingerprint != record.Fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, ErrorBody(c, "Idempotency-Key was already used with a different request"))
+		return
+	}
+
+	if !stored.Completed() {
+		retryAfter := time.Until(stored.LockedAt.Add(idempotencyLockTimeout))
+		c.Header("Retry-After", strconv.Itoa(max(1, int(retryAfter.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusConflict, ErrorBody(c, "A request with this Idempotency-Key is in progress"))
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	if stored.ContentType != "" {
+		c.Header("Content-Type", stored.ContentType)
+	}
+	c.Status(stored.StatusCode)
+	_, _ = c.Writer.Write(stored.ResponseBody)
+	c.Abort()
+}
+
+// idempotencyStoreKey is the key a request's record is stored under. User
+// IDs can't contain '/', so no two users' keys can collide.
+func idempotencyStoreKey(user, key string) string {
+	return user + "/" + key
+}
+
+// requestFingerprint identifies a request by method, path and body, so a
+// key reused for another endpoint counts as a different request too.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.RequestURI()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of everything written to the response.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
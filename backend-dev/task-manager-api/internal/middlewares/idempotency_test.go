@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/memory"
+	"github.com/rs/zerolog"
+)
+
+func TestIdempotencyLimitsBody(t *testing.T) {
+	const maxBody = 16
+
+	router := gin.New()
+	router.Use(Idempotency(memory.NewIdempotencyRepository(), maxBody, zerolog.Nop()))
+	router.POST("/tasks", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusCreated, "%d", len(body))
+	})
+
+	tests := []struct {
+		name   string
+		key    string
+		body   string
+		status int
+	}{
+		{name: "at the limit", key: "a", body: strings.Repeat("x", maxBody), status: http.StatusCreated},
+		{name: "over the limit", key: "b", body: strings.Repeat("x", maxBody+1), status: http.StatusRequestEntityTooLarge},
+		{name: "over the limit without a key", body: strings.Repeat("x", maxBody+1), status: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(tt.body))
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if want := strconv.Itoa(len(tt.body)); w.Code == http.StatusCreated && w.Body.String() != want {
+				t.Errorf("handler read %s bytes, want %s", w.Body, want)
+			}
+		})
+	}
+}
@@ -1,16 +1,24 @@
 package middleware
 
 import (
+	"slices"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 )
 
+// secretParams are path parameters that work as credentials, such as the
+// token of a calendar feed.
+var secretParams = []string{"token"}
+
+// Logger logs every request with the request-scoped logger set up by
+// RequestID, falling back to log when there is none. Requests to routes
+// with a secret parameter are logged with the route instead of the path.
 func Logger(log zerolog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		path := c.Request.URL.Path
+		path := loggedPath(c)
 		raw := c.Request.URL.RawQuery
 
 		if raw != "" {
@@ -24,7 +32,7 @@ func Logger(log zerolog.Logger) gin.HandlerFunc {
 		end := time.Now()
 		latency := end.Sub(start)
 
-		log.Info().
+		requestLogger(c, log).Info().
 			Str("method", c.Request.Method).
 			Str("path", path).
 			Int("status", c.Writer.Status()).
@@ -33,3 +41,23 @@ func Logger(log zerolog.Logger) gin.HandlerFunc {
 			Msg("API request")
 	}
 }
+
+// loggedPath returns the request's path, or its route when one of the
+// route's parameters is secret.
+func loggedPath(c *gin.Context) string {
+	for _, p := range c.Params {
+		if slices.Contains(secretParams, p.Key) {
+			return c.FullPath()
+		}
+	}
+	return c.Request.URL.Path
+}
+
+// requestLogger returns the logger RequestID stored on the request context,
+// or fallback for requests that did not go through it.
+func requestLogger(c *gin.Context, fallback zerolog.Logger) *zerolog.Logger {
+	if l := zerolog.Ctx(c.Request.Context()); l.GetLevel() != zerolog.Disabled {
+		return l
+	}
+	return &fallback
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestLoggerRedactsSecretParams(t *testing.T) {
+	var buf bytes.Buffer
+	router := gin.New()
+	router.Use(Logger(zerolog.New(&buf)))
+	router.GET("/calendar/:token", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/tasks/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		path     string
+		logged   string
+		unlogged string
+	}{
+		{path: "/calendar/s3cr3t-feed-token.ics", logged: `"path":"/calendar/:token"`, unlogged: "s3cr3t-feed-token"},
+		{path: "/calendar/s3cr3t-feed-token.ics?status=todo", logged: `"path":"/calendar/:token?status=todo"`, unlogged: "s3cr3t-feed-token"},
+		{path: "/api/v1/tasks/42", logged: `"path":"/api/v1/tasks/42"`},
+		{path: "/nowhere", logged: `"path":"/nowhere"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			buf.Reset()
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			line := buf.String()
+			if !strings.Contains(line, tt.logged) {
+				t.Errorf("log line %s does not contain %s", line, tt.logged)
+			}
+			if tt.unlogged != "" && strings.Contains(line, tt.unlogged) {
+				t.Errorf("log line %s contains %s", line, tt.unlogged)
+			}
+		})
+	}
+}
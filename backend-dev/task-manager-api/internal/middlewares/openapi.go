@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
+	"github.com/rs/zerolog"
+)
+
+func init() {
+	// Media types the API accepts that the validator can't decode out of
+	// the box. Line-delimited JSON is only checked for being a string.
+	openapi3filter.RegisterBodyDecoder("application/merge-patch+json", openapi3filter.JSONBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/jsonl", openapi3filter.FileBodyDecoder)
+
+	// Keep schema and value dumps out of error messages sent to clients.
+	openapi3.SchemaErrorDetailsDisabled = true
+}
+
+// OpenAPIValidator checks traffic against the OpenAPI document. Invalid
+// requests are rejected with 400 before they reach a handler. Invalid
+// responses have already been sent, so they are only logged; turn that on
+// in development and CI to catch the spec drifting from the handlers.
+// Requests to paths the document doesn't describe pass through untouched.
+func OpenAPIValidator(doc *openapi3.T, cfg config.OpenAPIConfig, log zerolog.Logger) (gin.HandlerFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.ValidateRequests && !cfg.ValidateResponses {
+			c.Next()
+			return
+		}
+
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if cfg.ValidateRequests {
+			if err := openapi3filter.ValidateRequest(c.Request.Context(), input); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, ErrorBody(c, err.Error()))
+				return
+			}
+		}
+
+		// WebSocket upgrades hijack the connection, so there is no
+		// response to check.
+		if !cfg.ValidateResponses || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		// Streamed bodies such as exports and calendars are not buffered;
+		// only their status and headers are checked.
+		checkBody := jsonResponses(route)
+		var recorder *responseRecorder
+		if checkBody {
+			recorder = &responseRecorder{ResponseWriter: c.Writer}
+			c.Writer = recorder
+		}
+
+		c.Next()
+
+		var body []byte
+		if recorder != nil {
+			body = recorder.body.Bytes()
+		}
+		err = openapi3filter.ValidateResponse(c.Request.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 c.Writer.Status(),
+			Header:                 c.Writer.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(body)),
+			Options: &openapi3filter.Options{
+				IncludeResponseStatus: true,
+				ExcludeResponseBody:   !checkBody,
+			},
+		})
+		if err != nil {
+			requestLogger(c, log).Error().Err(err).
+				Str("method", c.Request.Method).
+				Str("path", route.Path).
+				Int("status", c.Writer.Status()).
+				Msg("Response does not match the OpenAPI document")
+		}
+	}, nil
+}
+
+// jsonResponses reports whether every documented response of the route is
+// JSON or empty.
+func jsonResponses(route *routers.Route) bool {
+	for _, response := range route.Operation.Responses.Map() {
+		for mediaType := range response.Value.Content {
+			if mt, _, _ := mime.ParseMediaType(mediaType); mt != "application/json" {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	// RequestIDKey is the gin context key holding the request ID.
+	RequestIDKey = "request_id"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID reuses the caller's X-Request-ID when it looks sane and
+// generates one otherwise. The ID is echoed in the response and attached to
+// a request-scoped logger stored on the request context, so code further
+// down can log with zerolog.Ctx(ctx). When the request is traced the trace
+// ID is logged as well.
+func RequestID(log zerolog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+
+		logCtx := log.With().Str("request_id", id)
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			logCtx = logCtx.Stringer("trace_id", sc.TraceID())
+		}
+		reqLog := logCtx.Logger()
+		c.Request = c.Request.WithContext(reqLog.WithContext(c.Request.Context()))
+
+		c.Next()
+	}
+}
+
+// ErrorBody is the JSON body of every error response.
+func ErrorBody(c *gin.Context, message string) gin.H {
+	body := gin.H{"error": message}
+	if id := c.GetString(RequestIDKey); id != "" {
+		body["request_id"] = id
+	}
+	return body
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+// Package notify delivers notifications to users.
+package notify
+
+import (
+	"context"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/rs/zerolog"
+)
+
+// Notifier delivers a notification. It is called once the change it is
+// about is stored, while the request waits, so slow channels should queue
+// the notification and deliver it in the background. A failure is logged
+// and otherwise ignored.
+type Notifier interface {
+	Notify(ctx context.Context, n domain.Notification) error
+}
+
+// Log is a Notifier that only logs notifications, with the logger in ctx.
+// It stands in until a channel such as e-mail or chat is set up.
+type Log struct{}
+
+func (Log) Notify(ctx context.Context, n domain.Notification) error {
+	zerolog.Ctx(ctx).Info().
+		Str("kind", string(n.Kind)).
+		Str("user", n.User).
+		Str("actor", n.Actor).
+		Stringer("task_id", n.Task.ID).
+		Msg("Notification")
+	return nil
+}
@@ -0,0 +1,226 @@
+// Package policy decides what a user may do. Permissions are granted by
+// the user's role in a project and, for API tokens, limited by the token's
+// scopes; the tables below are the only place that says which role and
+// scope hold which permission and which permission each route needs.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+)
+
+// Permission names something a user may do in a project.
+type Permission string
+
+const (
+	TasksRead      Permission = "tasks:read"
+	TasksWrite     Permission = "tasks:write"
+	TasksDelete    Permission = "tasks:delete"
+	BoardsManage   Permission = "boards:manage"
+	ProjectsRead   Permission = "projects:read"
+	ProjectsUpdate Permission = "projects:update"
+	ProjectsDelete Permission = "projects:delete"
+	MembersManage  Permission = "members:manage"
+)
+
+// rolePermissions lists what each role may do. Every role has the
+// permissions of the roles below it.
+var rolePermissions = map[domain.ProjectRole][]Permission{
+	domain.ProjectRoleViewer:     {TasksRead, ProjectsRead},
+	domain.ProjectRoleMember:     {TasksRead, ProjectsRead, TasksWrite},
+	domain.ProjectRoleMaintainer: {TasksRead, ProjectsRead, TasksWrite, TasksDelete, BoardsManage, ProjectsUpdate},
+	domain.ProjectRoleOwner:      {TasksRead, ProjectsRead, TasksWrite, TasksDelete, BoardsManage, ProjectsUpdate, ProjectsDelete, MembersManage},
+}
+
+// permissionScopes lists the least scope an API token needs for each
+// permission. Scopes include the ones below them, see domain.TokenScopes.
+var permissionScopes = map[Permission]domain.TokenScope{
+	TasksRead:      domain.TokenScopeTasksRead,
+	ProjectsRead:   domain.TokenScopeTasksRead,
+	TasksWrite:     domain.TokenScopeTasksWrite,
+	TasksDelete:    domain.TokenScopeTasksWrite,
+	BoardsManage:   domain.TokenScopeTasksWrite,
+	ProjectsUpdate: domain.TokenScopeAdmin,
+	ProjectsDelete: domain.TokenScopeAdmin,
+	MembersManage:  domain.TokenScopeAdmin,
+}
+
+// ScopeFor returns the least scope an API token needs for permission.
+func ScopeFor(permission Permission) domain.TokenScope {
+	if scope, ok := permissionScopes[permission]; ok {
+		return scope
+	}
+	return domain.TokenScopeAdmin
+}
+
+// Allows reports whether role holds permission.
+func Allows(role domain.ProjectRole, permission Permission) bool {
+	return slices.Contains(rolePermissions[role], permission)
+}
+
+// DeniedError is returned when the user lacks Permission in the project,
+// or outside any project when ProjectID is nil. It matches
+// errs.ErrForbidden.
+type DeniedError struct {
+	Permission Permission
+	ProjectID  *uuid.UUID
+}
+
+func (e *DeniedError) Error() string {
+	if e.ProjectID == nil {
+		return fmt.Sprintf("missing permission %s outside a project, only project roles grant it", e.Permission)
+	}
+	return fmt.Sprintf("missing permission %s in project %s", e.Permission, *e.ProjectID)
+}
+
+func (e *DeniedError) Unwrap() error {
+	return errs.ErrForbidden
+}
+
+// ScopeError is returned when the caller's API token lacks Scope. It
+// matches errs.ErrForbidden.
+type ScopeError struct {
+	Scope domain.TokenScope
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("token lacks the %s scope", e.Scope)
+}
+
+func (e *ScopeError) Unwrap() error {
+	return errs.ErrForbidden
+}
+
+type userKey struct{}
+
+type scopesKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// User returns the authenticated user carried by ctx, or "".
+func User(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// WithScopes returns a copy of ctx limited to scopes, for a caller using an
+// API token.
+func WithScopes(ctx context.Context, scopes []domain.TokenScope) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// Scopes returns the scopes ctx is limited to. It reports false when ctx
+// isn't limited: the caller signed in with a JWT, or is anonymous.
+func Scopes(ctx context.Context) ([]domain.TokenScope, bool) {
+	scopes, ok := ctx.Value(scopesKey{}).([]domain.TokenScope)
+	return scopes, ok
+}
+
+// RequireScope fails with a *ScopeError when ctx is limited to scopes none
+// of which includes scope. Scopes apply whether or not the policy is
+// enforced.
+func RequireScope(ctx context.Context, scope domain.TokenScope) error {
+	scopes, limited := Scopes(ctx)
+	if !limited || slices.ContainsFunc(scopes, func(s domain.TokenScope) bool { return s.Includes(scope) }) {
+		return nil
+	}
+	return &ScopeError{Scope: scope}
+}
+
+// Policy checks the caller's permissions against their project roles. A
+// Policy that isn't enforced allows everything, so the API keeps working
+// without authentication.
+type Policy struct {
+	projects repository.ProjectRepository
+	enforce  bool
+}
+
+func New(projects repository.ProjectRepository, enforce bool) *Policy {
+	return &Policy{projects: projects, enforce: enforce}
+}
+
+// Enforced reports whether the policy checks anything.
+func (p *Policy) Enforced() bool {
+	return p.enforce
+}
+
+// SignedIn fails with errs.ErrUnauthenticated when ctx carries no user.
+func (p *Policy) SignedIn(ctx context.Context) error {
+	if p.enforce && User(ctx) == "" {
+		return fmt.Errorf("%w: sign in to use this API", errs.ErrUnauthenticated)
+	}
+	return nil
+}
+
+// Authorize fails unless the caller holds permission in the project.
+// Nobody has a role outside a project, so with the policy enforced nothing
+// is allowed on a nil project. It fails with errs.ErrUnauthenticated when
+// ctx carries no user, with a *ScopeError when the caller's API token
+// doesn't cover permission and with a *DeniedError when the user lacks it.
+func (p *Policy) Authorize(ctx context.Context, project *uuid.UUID, permission Permission) error {
+	if err := RequireScope(ctx, ScopeFor(permission)); err != nil {
+		return err
+	}
+	if err := p.SignedIn(ctx); err != nil || !p.enforce {
+		return err
+	}
+	if project == nil {
+		return &DeniedError{Permission: permission}
+	}
+
+	roles, err := p.projects.Roles(ctx, User(ctx))
+	if err != nil {
+		return err
+	}
+	if !Allows(roles[*project], permission) {
+		return &DeniedError{Permission: permission, ProjectID: project}
+	}
+	return nil
+}
+
+// Permitted returns the projects in which the caller holds permission.
+// Nothing outside a project is permitted then. It reports false, and no
+// projects, when the policy isn't enforced and everything is permitted.
+func (p *Policy) Permitted(ctx context.Context, permission Permission) ([]uuid.UUID, bool, error) {
+	if err := RequireScope(ctx, ScopeFor(permission)); err != nil {
+		return nil, false, err
+	}
+	if err := p.SignedIn(ctx); err != nil || !p.enforce {
+		return nil, false, err
+	}
+
+	roles, err := p.projects.Roles(ctx, User(ctx))
+	if err != nil {
+		return nil, false, err
+	}
+
+	ids := []uuid.UUID{}
+	for id, role := range roles {
+		if Allows(role, permission) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, true, nil
+}
+
+// Restrict narrows filter down to the tasks the caller may read.
+func (p *Policy) Restrict(ctx context.Context, filter *domain.TaskFilter) error {
+	ids, restricted, err := p.Permitted(ctx, TasksRead)
+	if err != nil {
+		return err
+	}
+	if restricted {
+		filter.RestrictProjects = true
+		filter.Projects = ids
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package policy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/memory"
+)
+
+func TestAuthorizeWithoutProject(t *testing.T) {
+	projects := memory.NewProjectRepository()
+	project := &domain.Project{ID: uuid.New(), Name: "Owned"}
+	owner := &domain.Membership{ProjectID: project.ID, User: "olivia", Role: domain.ProjectRoleOwner}
+	if err := projects.Create(context.Background(), project, owner); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		enforce bool
+		want    error
+	}{
+		{"anonymous, not enforced", context.Background(), false, nil},
+		{"user, not enforced", policy.WithUser(context.Background(), "otto"), false, nil},
+		{"anonymous", context.Background(), true, errs.ErrUnauthenticated},
+		{"project owner", policy.WithUser(context.Background(), "olivia"), true, errs.ErrForbidden},
+		{"outsider", policy.WithUser(context.Background(), "otto"), true, errs.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := policy.New(projects, tt.enforce)
+			for _, permission := range []policy.Permission{policy.TasksRead, policy.TasksWrite, policy.TasksDelete} {
+				err := p.Authorize(tt.ctx, nil, permission)
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("Authorize(nil, %s) = %v, want %v", permission, err, tt.want)
+				}
+
+				var denied *policy.DeniedError
+				if errors.As(err, &denied) && (denied.Permission != permission || denied.ProjectID != nil) {
+					t.Errorf("Authorize(nil, %s) = %#v", permission, denied)
+				}
+			}
+		})
+	}
+}
+
+func TestRestrictLeavesOutTasksWithoutProject(t *testing.T) {
+	p := policy.New(memory.NewProjectRepository(), true)
+
+	var filter domain.TaskFilter
+	if err := p.Restrict(policy.WithUser(context.Background(), "otto"), &filter); err != nil {
+		t.Fatal(err)
+	}
+	if !filter.RestrictProjects || len(filter.Projects) != 0 {
+		t.Fatalf("filter = %+v, want restricted to no projects", filter)
+	}
+
+	tasks := memory.NewTaskRepository()
+	if err := tasks.Create(context.Background(), &domain.Task{ID: uuid.New(), Title: "Unscoped"}); err != nil {
+		t.Fatal(err)
+	}
+	err := tasks.Find(context.Background(), filter, func(task *domain.Task) error {
+		t.Errorf("found %q, which is in no project", task.Title)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
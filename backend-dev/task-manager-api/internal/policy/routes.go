@@ -0,0 +1,134 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+)
+
+// Access is what a route needs from the caller while the policy is
+// enforced. Public routes need nothing. Every other route needs a signed-in
+// user and, when Permission is set, that permission in the project the
+// route acts on: the task's project, or the project in the path. Routes
+// without a Permission are open to every user but only show them the
+// tasks they may read.
+//
+// API tokens also need a scope for every route that isn't public, whether
+// or not the policy is enforced: Scope when it is set, otherwise the one
+// ScopeFor gives Permission.
+type Access struct {
+	Public     bool
+	Permission Permission
+	Scope      domain.TokenScope
+}
+
+// TokenScope returns the least scope an API token needs for the route.
+func (a Access) TokenScope() domain.TokenScope {
+	if a.Scope != "" {
+		return a.Scope
+	}
+	return ScopeFor(a.Permission)
+}
+
+// Routes lists every route of the HTTP API, as "METHOD pattern". The server
+// refuses to start while a route is missing; see CheckRoutes. The services
+// do the project checks, this table only enforces sign-in and token scopes
+// by itself, so keep both in step; TestRoutes checks that they are.
+var Routes = map[string]Access{
+	"GET /livez":  {Public: true},
+	"GET /readyz": {Public: true},
+	"GET /health": {Public: true},
+
+	"GET /api/v1/tasks":                         {Permission: TasksRead},
+	"POST /api/v1/tasks":                        {Permission: TasksWrite},
+	"GET /api/v1/tasks/export":                  {Permission: TasksRead},
+	"POST /api/v1/tasks/import":                 {Permission: TasksWrite},
+	"GET /api/v1/tasks/:id":                     {Permission: TasksRead},
+	"PUT /api/v1/tasks/:id":                     {Permission: TasksWrite},
+	"PATCH /api/v1/tasks/:id":                   {Permission: TasksWrite},
+	"DELETE /api/v1/tasks/:id":                  {Permission: TasksDelete},
+	"POST /api/v1/tasks/:id/move":               {Permission: TasksWrite},
+	"POST /api/v1/tasks/:id/assignees":          {Permission: TasksWrite},
+	"DELETE /api/v1/tasks/:id/assignees/:user":  {Permission: TasksWrite},
+	"POST /api/v1/tasks/:id/watchers":           {Permission: TasksWrite, Scope: domain.TokenScopeTasksRead}, // tasks:read to watch it yourself
+	"DELETE /api/v1/tasks/:id/watchers/:user":   {Permission: TasksWrite, Scope: domain.TokenScopeTasksRead}, // tasks:read to stop watching it yourself
+	"GET /api/v1/tasks/:id/history":             {Permission: TasksRead},
+	"POST /api/v1/tasks:action":                 {Permission: TasksWrite}, // tasks:delete for deletions
+	"GET /api/v1/projects":                      {Permission: ProjectsRead},
+	"POST /api/v1/projects":                     {Scope: domain.TokenScopeAdmin},
+	"GET /api/v1/projects/:id":                  {Permission: ProjectsRead},
+	"PUT /api/v1/projects/:id":                  {Permission: ProjectsUpdate},
+	"DELETE /api/v1/projects/:id":               {Permission: ProjectsDelete},
+	"GET /api/v1/projects/:id/members":          {Permission: ProjectsRead},
+	"PUT /api/v1/projects/:id/members/:user":    {Permission: MembersManage},
+	"DELETE /api/v1/projects/:id/members/:user": {Permission: MembersManage},
+	"POST /api/v1/calendar-feeds":               {Scope: domain.TokenScopeAdmin}, // the feed token is a credential too
+	"DELETE /api/v1/calendar-feeds/:id":         {Scope: domain.TokenScopeAdmin},
+	"GET /api/v1/tokens":                        {Scope: domain.TokenScopeAdmin},
+	"POST /api/v1/tokens":                       {Scope: domain.TokenScopeAdmin},
+	"GET /api/v1/tokens/:id":                    {Scope: domain.TokenScopeAdmin},
+	"DELETE /api/v1/tokens/:id":                 {Scope: domain.TokenScopeAdmin},
+	"GET /api/v1/views":                         {Scope: domain.TokenScopeTasksRead},
+	"POST /api/v1/views":                        {Scope: domain.TokenScopeTasksWrite},
+	"GET /api/v1/views/:id":                     {Scope: domain.TokenScopeTasksRead},
+	"PUT /api/v1/views/:id":                     {Scope: domain.TokenScopeTasksWrite},
+	"DELETE /api/v1/views/:id":                  {Scope: domain.TokenScopeTasksWrite},
+	"GET /api/v1/views/:id/tasks":               {Scope: domain.TokenScopeTasksRead},
+	"GET /api/v1/boards":                        {Permission: TasksRead},
+	"POST /api/v1/boards":                       {Permission: BoardsManage},
+	"GET /api/v1/boards/:id":                    {Permission: TasksRead},
+	"PUT /api/v1/boards/:id":                    {Permission: BoardsManage},
+	"DELETE /api/v1/boards/:id":                 {Permission: BoardsManage},
+	"GET /api/v1/boards/:id/versions":           {Permission: TasksRead},
+
+	// The feed token stands in for the user; the feed shows the tasks its
+	// owner may read.
+	"GET /calendar/:token": {Public: true},
+
+	// GraphQL runs the same service checks as REST, scopes included.
+	"GET /graphql":            {Scope: domain.TokenScopeTasksRead},
+	"POST /graphql":           {Scope: domain.TokenScopeTasksRead},
+	"GET /graphql/playground": {Public: true},
+	"GET /openapi.json":       {Public: true},
+	"GET /docs":               {Public: true},
+}
+
+// CheckRoutes reports every registered route missing from Routes, every
+// route that isn't public but needs neither a permission nor a scope, and
+// every entry of Routes without a route, except the optional GraphQL
+// playground.
+func CheckRoutes(routes gin.RoutesInfo) error {
+	registered := make(map[string]bool, len(routes))
+	var problems []string
+	for _, r := range routes {
+		if r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			continue
+		}
+		key := r.Method + " " + r.Path
+		registered[key] = true
+		access, ok := Routes[key]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s has no access rule", key))
+		case !access.Public && access.Permission == "" && access.Scope == "":
+			problems = append(problems, fmt.Sprintf("%s needs a permission or a token scope", key))
+		}
+	}
+
+	for key := range Routes {
+		if !registered[key] && key != "GET /graphql/playground" {
+			problems = append(problems, fmt.Sprintf("%s has an access rule but no route", key))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.Sort(problems)
+	return errors.New("routes and policy.Routes differ:\n  " + strings.Join(problems, "\n  "))
+}
@@ -0,0 +1,560 @@
+package policy_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/server"
+	"github.com/rs/zerolog"
+)
+
+const jwtSecret = "0123456789abcdef0123456789abcdef"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// caller is who sends a request: anonymous, a user with a role in the
+// fixture project, or an API token of its owner.
+type caller struct {
+	name  string
+	user  string
+	role  domain.ProjectRole
+	scope domain.TokenScope
+}
+
+var callers = []caller{
+	{name: "anonymous"},
+	{name: "owner", user: "olivia", role: domain.ProjectRoleOwner},
+	{name: "maintainer", user: "mia", role: domain.ProjectRoleMaintainer},
+	{name: "member", user: "max", role: domain.ProjectRoleMember},
+	{name: "viewer", user: "vera", role: domain.ProjectRoleViewer},
+	{name: "outsider", user: "otto"},
+	{name: "token tasks:read", user: "olivia", role: domain.ProjectRoleOwner, scope: domain.TokenScopeTasksRead},
+	{name: "token tasks:write", user: "olivia", role: domain.ProjectRoleOwner, scope: domain.TokenScopeTasksWrite},
+	{name: "token admin", user: "olivia", role: domain.ProjectRoleOwner, scope: domain.TokenScopeAdmin},
+}
+
+// request is what the matrix sends to a route.
+type request struct {
+	path        string
+	contentType string
+	body        string
+	// inProject is set when the request acts on the fixture project, so the
+	// route's permission is checked there. Other requests only need a user.
+	inProject bool
+	// permission overrides the route's permission for requests that need
+	// less, such as users watching a task themselves.
+	permission policy.Permission
+	// deniedStatus replaces the 403 of a missing permission in the project
+	// for routes that report it otherwise, like batches do per operation.
+	deniedStatus int
+}
+
+// requests has one request for every entry of policy.Routes.
+var requests = map[string]func(f *fixture) request{
+	"GET /livez":  func(*fixture) request { return request{path: "/livez"} },
+	"GET /readyz": func(*fixture) request { return request{path: "/readyz"} },
+	"GET /health": func(*fixture) request { return request{path: "/health"} },
+
+	"GET /api/v1/tasks": func(*fixture) request { return request{path: "/api/v1/tasks"} },
+	"POST /api/v1/tasks": func(f *fixture) request {
+		return request{path: "/api/v1/tasks", body: fmt.Sprintf(`{"title":"New","project_id":%q}`, f.project()), inProject: true}
+	},
+	"GET /api/v1/tasks/export": func(*fixture) request { return request{path: "/api/v1/tasks/export?format=jsonl"} },
+	"POST /api/v1/tasks/import": func(f *fixture) request {
+		body := fmt.Sprintf(`{"title":"Imported","project_id":%q}`, f.project()) + "\n"
+		return request{path: "/api/v1/tasks/import?format=jsonl", contentType: "application/x-ndjson", body: body, inProject: true}
+	},
+	"GET /api/v1/tasks/:id": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task(), inProject: true}
+	},
+	"PUT /api/v1/tasks/:id": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task(), body: `{"title":"Renamed"}`, inProject: true}
+	},
+	"PATCH /api/v1/tasks/:id": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task(), contentType: "application/merge-patch+json", body: `{"description":"Patched"}`, inProject: true}
+	},
+	"DELETE /api/v1/tasks/:id": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task(), inProject: true}
+	},
+	"POST /api/v1/tasks/:id/move": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task() + "/move", body: fmt.Sprintf(`{"after":%q}`, f.otherTask()), inProject: true}
+	},
+	"POST /api/v1/tasks/:id/assignees": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task() + "/assignees", body: `{"user":"max"}`, inProject: true}
+	},
+	"DELETE /api/v1/tasks/:id/assignees/:user": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task() + "/assignees/max", inProject: true}
+	},
+	"POST /api/v1/tasks/:id/watchers": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task() + "/watchers", body: `{"user":"me"}`, inProject: true, permission: policy.TasksRead}
+	},
+	"DELETE /api/v1/tasks/:id/watchers/:user": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task() + "/watchers/me", inProject: true, permission: policy.TasksRead}
+	},
+	"GET /api/v1/tasks/:id/history": func(f *fixture) request {
+		return request{path: "/api/v1/tasks/" + f.task() + "/history", inProject: true}
+	},
+	"POST /api/v1/tasks:action": func(f *fixture) request {
+		body := fmt.Sprintf(`{"operations":[{"op":"create","data":{"title":"Batch","project_id":%q}}]}`, f.project())
+		return request{path: "/api/v1/tasks:batch", body: body, inProject: true, deniedStatus: http.StatusUnprocessableEntity}
+	},
+
+	"GET /api/v1/projects":  func(*fixture) request { return request{path: "/api/v1/projects"} },
+	"POST /api/v1/projects": func(*fixture) request { return request{path: "/api/v1/projects", body: `{"name":"Another"}`} },
+	"GET /api/v1/projects/:id": func(f *fixture) request {
+		return request{path: "/api/v1/projects/" + f.project(), inProject: true}
+	},
+	"PUT /api/v1/projects/:id": func(f *fixture) request {
+		return request{path: "/api/v1/projects/" + f.project(), body: `{"name":"Renamed"}`, inProject: true}
+	},
+	"DELETE /api/v1/projects/:id": func(f *fixture) request {
+		return request{path: "/api/v1/projects/" + f.project(), inProject: true}
+	},
+	"GET /api/v1/projects/:id/members": func(f *fixture) request {
+		return request{path: "/api/v1/projects/" + f.project() + "/members", inProject: true}
+	},
+	"PUT /api/v1/projects/:id/members/:user": func(f *fixture) request {
+		return request{path: "/api/v1/projects/" + f.project() + "/members/nina", body: `{"role":"viewer"}`, inProject: true}
+	},
+	"DELETE /api/v1/projects/:id/members/:user": func(f *fixture) request {
+		return request{path: "/api/v1/projects/" + f.project() + "/members/leo", inProject: true}
+	},
+
+	"POST /api/v1/calendar-feeds": func(f *fixture) request {
+		return request{path: "/api/v1/calendar-feeds", body: fmt.Sprintf(`{"owner":%q}`, f.user())}
+	},
+	"DELETE /api/v1/calendar-feeds/:id": func(f *fixture) request {
+		id, _ := f.feed()
+		return request{path: "/api/v1/calendar-feeds/" + id}
+	},
+
+	"GET /api/v1/tokens": func(*fixture) request { return request{path: "/api/v1/tokens"} },
+	"POST /api/v1/tokens": func(*fixture) request {
+		return request{path: "/api/v1/tokens", body: `{"name":"ci","scopes":["tasks:read"]}`}
+	},
+	"GET /api/v1/tokens/:id": func(f *fixture) request {
+		return request{path: "/api/v1/tokens/" + f.token()}
+	},
+	"DELETE /api/v1/tokens/:id": func(f *fixture) request {
+		return request{path: "/api/v1/tokens/" + f.token()}
+	},
+
+	"GET /api/v1/views": func(*fixture) request { return request{path: "/api/v1/views"} },
+	"POST /api/v1/views": func(f *fixture) request {
+		return request{path: "/api/v1/views", body: fmt.Sprintf(`{"name":"Mine","owner":%q}`, f.user())}
+	},
+	"GET /api/v1/views/:id": func(f *fixture) request { return request{path: "/api/v1/views/" + f.view()} },
+	"PUT /api/v1/views/:id": func(f *fixture) request {
+		return request{path: "/api/v1/views/" + f.view(), body: `{"name":"Renamed"}`}
+	},
+	"DELETE /api/v1/views/:id":    func(f *fixture) request { return request{path: "/api/v1/views/" + f.view()} },
+	"GET /api/v1/views/:id/tasks": func(f *fixture) request { return request{path: "/api/v1/views/" + f.view() + "/tasks"} },
+
+	"GET /api/v1/boards": func(*fixture) request { return request{path: "/api/v1/boards"} },
+	"POST /api/v1/boards": func(f *fixture) request {
+		return request{path: "/api/v1/boards", body: fmt.Sprintf(`{"project_id":%q,"name":"New","columns":[{"name":"Todo","statuses":["TODO"]}]}`, f.project()), inProject: true}
+	},
+	"GET /api/v1/boards/:id": func(f *fixture) request {
+		return request{path: "/api/v1/boards/" + f.board(), inProject: true}
+	},
+	"PUT /api/v1/boards/:id": func(f *fixture) request {
+		return request{path: "/api/v1/boards/" + f.board(), body: `{"version":1,"name":"Renamed"}`, inProject: true}
+	},
+	"DELETE /api/v1/boards/:id": func(f *fixture) request {
+		return request{path: "/api/v1/boards/" + f.board(), inProject: true}
+	},
+	"GET /api/v1/boards/:id/versions": func(f *fixture) request {
+		return request{path: "/api/v1/boards/" + f.board() + "/versions", inProject: true}
+	},
+
+	"GET /calendar/:token": func(f *fixture) request {
+		_, token := f.feed()
+		return request{path: "/calendar/" + token + ".ics"}
+	},
+
+	"GET /graphql": func(*fixture) request {
+		return request{path: "/graphql?query=" + url.QueryEscape("{ tasks { edges { node { id } } } }")}
+	},
+	"POST /graphql": func(*fixture) request {
+		return request{path: "/graphql", body: `{"query":"{ tasks { edges { node { id } } } }"}`}
+	},
+	"GET /graphql/playground": func(*fixture) request { return request{path: "/graphql/playground"} },
+	"GET /openapi.json":       func(*fixture) request { return request{path: "/openapi.json"} },
+	"GET /docs":               func(*fixture) request { return request{path: "/docs"} },
+}
+
+// TestRoutes sends a request to every route of policy.Routes as every
+// caller, against the router cmd/api serves with the policy enforced, and
+// checks that exactly the callers the table lets through get a 2xx.
+func TestRoutes(t *testing.T) {
+	srv := newServer(t)
+
+	keys := make([]string, 0, len(policy.Routes))
+	for key := range policy.Routes {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		access := policy.Routes[key]
+		build, ok := requests[key]
+		if !ok {
+			t.Errorf("%s has no request in this test", key)
+			continue
+		}
+		method, _, _ := strings.Cut(key, " ")
+
+		for _, c := range callers {
+			t.Run(key+"/"+c.name, func(t *testing.T) {
+				f := &fixture{t: t, srv: srv, caller: c}
+				r := build(f)
+				w := f.do(f.auth(), method, r.path, r.contentType, r.body)
+
+				status, field, value := expect(access, r, c)
+				switch {
+				case status == http.StatusOK && (w.Code < 200 || w.Code > 299):
+					t.Fatalf("status %d, want 2xx: %s", w.Code, w.Body)
+				case status != http.StatusOK && w.Code != status:
+					t.Fatalf("status %d, want %d: %s", w.Code, status, w.Body)
+				}
+
+				if field != "" {
+					var body map[string]any
+					if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+						t.Fatalf("403 body is not JSON: %s", w.Body)
+					}
+					if body[field] != value {
+						t.Errorf("403 body has %s %v, want %s: %s", field, body[field], value, w.Body)
+					}
+				}
+			})
+		}
+	}
+}
+
+// expect returns the status the caller should get for r: 200 standing for
+// any 2xx, 401, 403 or r.deniedStatus. A 403 names the missing permission
+// or token scope, as field and value.
+func expect(access policy.Access, r request, c caller) (status int, field, value string) {
+	if access.Public {
+		return http.StatusOK, "", ""
+	}
+	if c.user == "" {
+		return http.StatusUnauthorized, "", ""
+	}
+
+	permission := access.Permission
+	if r.permission != "" {
+		permission = r.permission
+	}
+
+	if c.scope != "" {
+		needed := []domain.TokenScope{access.TokenScope()}
+		if permission != "" {
+			needed = append(needed, policy.ScopeFor(permission))
+		}
+		for _, scope := range needed {
+			if !c.scope.Includes(scope) {
+				return http.StatusForbidden, "scope", string(scope)
+			}
+		}
+	}
+
+	if r.inProject && !policy.Allows(c.role, permission) {
+		if r.deniedStatus != 0 {
+			return r.deniedStatus, "", ""
+		}
+		return http.StatusForbidden, "permission", string(permission)
+	}
+	return http.StatusOK, "", ""
+}
+
+// TestRevokeFeedOfAnotherUser covers what the matrix can't: its callers
+// only revoke their own feeds.
+func TestRevokeFeedOfAnotherUser(t *testing.T) {
+	f := &fixture{t: t, srv: newServer(t), caller: callers[1]}
+	id, _ := f.feed()
+	path := "/api/v1/calendar-feeds/" + id
+
+	if w := f.do(bearer("otto"), http.MethodDelete, path, "", ""); w.Code != http.StatusForbidden {
+		t.Errorf("revoking another user's feed: status %d, want 403: %s", w.Code, w.Body)
+	}
+	if w := f.do(bearer("olivia"), http.MethodDelete, path, "", ""); w.Code != http.StatusNoContent {
+		t.Errorf("revoking your own feed: status %d, want 204: %s", w.Code, w.Body)
+	}
+	if w := f.do(bearer("otto"), http.MethodDelete, path, "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("revoking a revoked feed: status %d, want 404: %s", w.Code, w.Body)
+	}
+}
+
+// TestTasksOutsideProjects checks that, with the policy enforced, tasks and
+// boards in no project are out of everyone's reach, project owners
+// included.
+func TestTasksOutsideProjects(t *testing.T) {
+	repos := server.MemoryRepositories()
+	f := &fixture{t: t, srv: newServerWith(t, repos), caller: callers[1]}
+	f.project()
+
+	now := time.Now()
+	task := &domain.Task{
+		ID:        uuid.New(),
+		Title:     "Legacy",
+		Status:    domain.TaskStatusTodo,
+		Priority:  domain.DefaultTaskPriority,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repos.Tasks.Create(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+	board := &domain.Board{
+		ID: uuid.New(),
+		BoardDefinition: domain.BoardDefinition{
+			Name:    "Legacy",
+			Columns: []domain.BoardColumn{{Name: "Todo", Statuses: []domain.TaskStatus{domain.TaskStatusTodo}}},
+		},
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := repos.Boards.Create(context.Background(), board); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/api/v1/tasks", `{"title":"Unscoped"}`},
+		{http.MethodGet, "/api/v1/tasks/" + task.ID.String(), ""},
+		{http.MethodPut, "/api/v1/tasks/" + task.ID.String(), `{"title":"Renamed"}`},
+		{http.MethodDelete, "/api/v1/tasks/" + task.ID.String(), ""},
+		{http.MethodPost, "/api/v1/tasks/" + task.ID.String() + "/watchers", `{"user":"me"}`},
+		{http.MethodPost, "/api/v1/boards", `{"name":"Unscoped","columns":[{"name":"Todo","statuses":["TODO"]}]}`},
+		{http.MethodGet, "/api/v1/boards/" + board.ID.String(), ""},
+	}
+	for _, user := range []string{"olivia", "vera", "otto"} {
+		for _, tt := range tests {
+			t.Run(user+"/"+tt.method+" "+tt.path, func(t *testing.T) {
+				w := f.do(bearer(user), tt.method, tt.path, "", tt.body)
+				if w.Code != http.StatusForbidden {
+					t.Fatalf("status %d, want 403: %s", w.Code, w.Body)
+				}
+			})
+		}
+
+		t.Run(user+"/listings", func(t *testing.T) {
+			for _, path := range []string{"/api/v1/tasks", "/api/v1/tasks/export?format=jsonl", "/api/v1/boards"} {
+				w := f.do(bearer(user), http.MethodGet, path, "", "")
+				if w.Code != http.StatusOK {
+					t.Fatalf("GET %s: status %d: %s", path, w.Code, w.Body)
+				}
+				if body := w.Body.String(); strings.Contains(body, task.ID.String()) || strings.Contains(body, board.ID.String()) {
+					t.Errorf("GET %s lists what is in no project: %s", path, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRoutesCoverPolicy(t *testing.T) {
+	for key := range requests {
+		if _, ok := policy.Routes[key]; !ok {
+			t.Errorf("%s is not in policy.Routes", key)
+		}
+	}
+}
+
+func newServer(t *testing.T) *server.Server {
+	t.Helper()
+	return newServerWith(t, server.MemoryRepositories())
+}
+
+func newServerWith(t *testing.T, repos server.Repositories) *server.Server {
+	t.Helper()
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DATABASE_DRIVER", "memory")
+	t.Setenv("JWT_SECRET", jwtSecret)
+	t.Setenv("AUTH_ENFORCE_POLICY", "true")
+	t.Setenv("GRAPHQL_PLAYGROUND", "true")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	srv, err := server.New(cfg, repos, server.Options{Log: zerolog.Nop()})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+	return srv
+}
+
+// fixture creates what a request needs on first use: a project owned by
+// olivia with one member of every role, tasks and a board in it, and views,
+// tokens and calendar feeds of the caller.
+type fixture struct {
+	t      *testing.T
+	srv    *server.Server
+	caller caller
+
+	projectID, taskID, otherTaskID, boardID, viewID, tokenID string
+	feedID, feedToken                                        string
+}
+
+func (f *fixture) project() string {
+	if f.projectID == "" {
+		f.projectID = f.create(bearer("olivia"), "/api/v1/projects", `{"name":"Fixture"}`)["id"].(string)
+		members := map[string]domain.ProjectRole{
+			"mia":  domain.ProjectRoleMaintainer,
+			"max":  domain.ProjectRoleMember,
+			"vera": domain.ProjectRoleViewer,
+			"leo":  domain.ProjectRoleViewer,
+		}
+		for user, role := range members {
+			path := "/api/v1/projects/" + f.projectID + "/members/" + user
+			f.mustDo(bearer("olivia"), http.MethodPut, path, fmt.Sprintf(`{"role":%q}`, role))
+		}
+	}
+	return f.projectID
+}
+
+func (f *fixture) task() string {
+	if f.taskID == "" {
+		f.taskID = f.newTask()
+	}
+	return f.taskID
+}
+
+func (f *fixture) otherTask() string {
+	if f.otherTaskID == "" {
+		f.otherTaskID = f.newTask()
+	}
+	return f.otherTaskID
+}
+
+func (f *fixture) newTask() string {
+	body := fmt.Sprintf(`{"title":"Fixture","project_id":%q}`, f.project())
+	return f.create(bearer("olivia"), "/api/v1/tasks", body)["id"].(string)
+}
+
+func (f *fixture) board() string {
+	if f.boardID == "" {
+		body := fmt.Sprintf(`{"project_id":%q,"name":"Fixture","columns":[{"name":"Todo","statuses":["TODO"]}]}`, f.project())
+		f.boardID = f.create(bearer("olivia"), "/api/v1/boards", body)["id"].(string)
+	}
+	return f.boardID
+}
+
+func (f *fixture) view() string {
+	if f.viewID == "" {
+		body := fmt.Sprintf(`{"name":"Fixture","owner":%q}`, f.user())
+		f.viewID = f.create(bearer(f.user()), "/api/v1/views", body)["id"].(string)
+	}
+	return f.viewID
+}
+
+func (f *fixture) token() string {
+	if f.tokenID == "" {
+		created := f.create(bearer(f.user()), "/api/v1/tokens", `{"name":"Fixture","scopes":["tasks:read"]}`)
+		f.tokenID = created["token"].(map[string]any)["id"].(string)
+	}
+	return f.tokenID
+}
+
+// feed returns the ID and the secret token of a calendar feed of the
+// caller.
+func (f *fixture) feed() (string, string) {
+	if f.feedID == "" {
+		body := fmt.Sprintf(`{"owner":%q}`, f.user())
+		created := f.create(bearer(f.user()), "/api/v1/calendar-feeds", body)
+		f.feedID = created["id"].(string)
+		f.feedToken = created["token"].(string)
+	}
+	return f.feedID, f.feedToken
+}
+
+// user is the caller's user, or olivia for anonymous callers, whose
+// requests are refused before reaching anything the fixture makes.
+func (f *fixture) user() string {
+	if f.caller.user == "" {
+		return "olivia"
+	}
+	return f.caller.user
+}
+
+// auth returns the Authorization header of the caller.
+func (f *fixture) auth() string {
+	switch {
+	case f.caller.user == "":
+		return ""
+	case f.caller.scope == "":
+		return bearer(f.caller.user)
+	}
+	body := fmt.Sprintf(`{"name":"Caller","scopes":[%q]}`, f.caller.scope)
+	return "Bearer " + f.create(bearer(f.caller.user), "/api/v1/tokens", body)["secret"].(string)
+}
+
+// create posts body to path and returns the decoded response.
+func (f *fixture) create(auth, path, body string) map[string]any {
+	f.t.Helper()
+	w := f.mustDo(auth, http.MethodPost, path, body)
+	var created map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		f.t.Fatalf("POST %s: %v", path, err)
+	}
+	return created
+}
+
+func (f *fixture) mustDo(auth, method, path, body string) *httptest.ResponseRecorder {
+	f.t.Helper()
+	w := f.do(auth, method, path, "", body)
+	if w.Code < 200 || w.Code > 299 {
+		f.t.Fatalf("fixture %s %s: status %d: %s", method, path, w.Code, w.Body)
+	}
+	return w
+}
+
+func (f *fixture) do(auth, method, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	w := httptest.NewRecorder()
+	f.srv.Router.ServeHTTP(w, req)
+	return w
+}
+
+// bearer returns an Authorization header with a JWT for user.
+func bearer(user string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   user,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	signed, err := token.SignedString([]byte(jwtSecret))
+	if err != nil {
+		panic(err)
+	}
+	return "Bearer " + signed
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for LRU.now.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestLRU(size int) (*LRU, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	lru := NewLRU(size)
+	lru.now = clock.Now
+	return lru, clock
+}
+
+func mustGet(t *testing.T, c *LRU, key string) (string, bool) {
+	t.Helper()
+	value, found, err := c.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%q): %v", key, err)
+	}
+	return string(value), found
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	lru, clock := newTestLRU(10)
+
+	if err := lru.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	clock.Advance(time.Minute - time.Nanosecond)
+	if value, found := mustGet(t, lru, "a"); !found || value != "1" {
+		t.Fatalf("Get just before the TTL = %q, %v; want 1, true", value, found)
+	}
+
+	clock.Advance(time.Nanosecond)
+	if _, found := mustGet(t, lru, "a"); found {
+		t.Fatal("Get at the TTL found the entry")
+	}
+	if len(lru.entries) != 0 || lru.order.Len() != 0 {
+		t.Errorf("expired entry is still held: %d entries, %d in order", len(lru.entries), lru.order.Len())
+	}
+}
+
+func TestLRUSetRenewsTTL(t *testing.T) {
+	ctx := context.Background()
+	lru, clock := newTestLRU(10)
+
+	_ = lru.Set(ctx, "a", []byte("1"), time.Minute)
+	clock.Advance(45 * time.Second)
+	_ = lru.Set(ctx, "a", []byte("2"), time.Minute)
+	clock.Advance(45 * time.Second)
+
+	if value, found := mustGet(t, lru, "a"); !found || value != "2" {
+		t.Errorf("Get after renewing = %q, %v; want 2, true", value, found)
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru, _ := newTestLRU(2)
+
+	_ = lru.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = lru.Set(ctx, "b", []byte("2"), time.Minute)
+	mustGet(t, lru, "a")
+	_ = lru.Set(ctx, "c", []byte("3"), time.Minute)
+
+	if _, found := mustGet(t, lru, "b"); found {
+		t.Error("b was used least recently but was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, found := mustGet(t, lru, key); !found {
+			t.Errorf("%s was evicted", key)
+		}
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	ctx := context.Background()
+	lru, _ := newTestLRU(10)
+
+	_ = lru.Set(ctx, "a", []byte("1"), time.Minute)
+	_ = lru.Set(ctx, "b", []byte("2"), time.Minute)
+	if err := lru.Delete(ctx, "a", "b", "missing"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if _, found := mustGet(t, lru, key); found {
+			t.Errorf("%s survived Delete", key)
+		}
+	}
+}
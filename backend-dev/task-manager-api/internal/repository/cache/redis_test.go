@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client, err := DialRedis(context.Background(), "redis://"+server.Addr()+"/0")
+	if err != nil {
+		t.Fatalf("DialRedis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewRedis(client, "tm:"), server
+}
+
+func TestRedisBackend(t *testing.T) {
+	ctx := context.Background()
+	backend, server := newTestRedis(t)
+
+	if _, found, err := backend.Get(ctx, "a"); err != nil || found {
+		t.Fatalf("Get of a missing key = %v, %v; want not found", found, err)
+	}
+
+	if err := backend.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := backend.Set(ctx, "b", []byte("2"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if value, found, err := backend.Get(ctx, "a"); err != nil || !found || string(value) != "1" {
+		t.Fatalf("Get = %q, %v, %v; want 1", value, found, err)
+	}
+	if !server.Exists("tm:a") || server.Exists("a") {
+		t.Error("keys are not stored under the prefix")
+	}
+	if ttl := server.TTL("tm:a"); ttl != time.Minute {
+		t.Errorf("TTL = %v, want 1m", ttl)
+	}
+
+	if err := backend.Delete(ctx, "a", "b", "missing"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if server.Exists("tm:a") || server.Exists("tm:b") {
+		t.Error("Delete left keys behind")
+	}
+}
+
+func TestRedisBackendExpires(t *testing.T) {
+	ctx := context.Background()
+	backend, server := newTestRedis(t)
+
+	_ = backend.Set(ctx, "a", []byte("1"), time.Minute)
+	server.FastForward(time.Minute)
+
+	if _, found, err := backend.Get(ctx, "a"); err != nil || found {
+		t.Errorf("Get after the TTL = %v, %v; want not found", found, err)
+	}
+}
+
+func TestDialRedisFails(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := DialRedis(ctx, "not a url"); err == nil {
+		t.Error("DialRedis with an invalid URL succeeded")
+	}
+
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+	if _, err := DialRedis(ctx, "redis://"+addr); err == nil {
+		t.Error("DialRedis without a server succeeded")
+	}
+}
+
+func TestTaskRepositoryOnRedis(t *testing.T) {
+	ctx := context.Background()
+	backend, server := newTestRedis(t)
+	f := newFixture(t, backend, 1)
+	id := f.tasks[0].ID
+	key := "tm:" + taskKey(id)
+
+	f.get(t, id)
+	f.get(t, id)
+	if loads := f.next.loads.Load(); loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+	if !server.Exists(key) {
+		t.Fatal("task was not cached in Redis")
+	}
+
+	task := *f.tasks[0]
+	task.Title = "Updated"
+	if err := f.cached.Update(ctx, &task); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if server.Exists(key) {
+		t.Error("Update left the task in Redis")
+	}
+	if got := f.get(t, id); got.Title != "Updated" {
+		t.Errorf("GetByID = %q, want Updated", got.Title)
+	}
+}
+
+// TestTaskRepositoryRedisDown checks that lookups go to the repository
+// while Redis is unreachable.
+func TestTaskRepositoryRedisDown(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	f := newFixture(t, NewRedis(client, "tm:"), 1)
+	id := f.tasks[0].ID
+
+	server.Close()
+	if got := f.get(t, id); got.ID != id {
+		t.Fatalf("GetByID = %s, want %s", got.ID, id)
+	}
+	if n := f.observed.count(Error); n != 1 {
+		t.Errorf("observed %d errors, want 1", n)
+	}
+}
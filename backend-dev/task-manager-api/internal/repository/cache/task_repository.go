@@ -0,0 +1,350 @@
+// Package cache puts a read-through cache in front of a TaskRepository.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/singleflight"
+)
+
+// Backend stores cache entries. Get reports a missing or expired key as
+// found == false with a nil error.
+type Backend interface {
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Result is the outcome of a lookup, for metrics.
+type Result string
+
+const (
+	Hit   Result = "hit"
+	Miss  Result = "miss"
+	Error Result = "error"
+)
+
+// TaskRepository caches GetByID in a Backend and passes everything else
+// through. Update, Delete and the user changes evict the task once the
+// change is visible: immediately, or when the transaction they ran in
+// commits. Reads inside a transaction skip the cache, as they may see
+// uncommitted changes.
+//
+// The cache fails open: when the backend errors, the lookup goes to the
+// wrapped repository and the error is only logged.
+type TaskRepository struct {
+	next    repository.TaskRepository
+	backend Backend
+	ttl     time.Duration
+	observe func(Result)
+
+	loads singleflight.Group
+	// epoch counts evictions. A load only fills the cache if no eviction
+	// happened while it ran, so it can't put back a task that was just
+	// changed. This protects the local process; other replicas sharing a
+	// Redis backend are covered by the TTL.
+	epoch atomic.Uint64
+}
+
+type Option func(*TaskRepository)
+
+// WithObserver reports the result of every cached lookup, e.g. to a metric.
+func WithObserver(observe func(Result)) Option {
+	return func(r *TaskRepository) { r.observe = observe }
+}
+
+// NewTaskRepository caches tasks from next in backend for ttl.
+func NewTaskRepository(next repository.TaskRepository, backend Backend, ttl time.Duration, opts ...Option) *TaskRepository {
+	r := &TaskRepository{
+		next:    next,
+		backend: backend,
+		ttl:     ttl,
+		observe: func(Result) {},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func taskKey(id uuid.UUID) string {
+	return "task:" + id.String()
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
+	key := taskKey(id)
+
+	data, found, err := r.backend.Get(ctx, key)
+	switch {
+	case err != nil:
+		r.observe(Error)
+		zerolog.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Task cache lookup failed")
+	case found:
+		var task domain.Task
+		if err := json.Unmarshal(data, &task); err == nil {
+			r.observe(Hit)
+			return &task, nil
+		}
+		r.observe(Error)
+		zerolog.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Discarding unreadable task cache entry")
+	default:
+		r.observe(Miss)
+	}
+
+	// Concurrent misses for the same task share one load. The load must
+	// not be cancelled with the first caller's request, so it runs without
+	// its deadline, and each caller stops waiting when its own is up.
+	loaded := r.loads.DoChan(key, func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		epoch := r.epoch.Load()
+
+		task, err := r.next.GetByID(loadCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		if r.epoch.Load() == epoch {
+			r.fill(loadCtx, key, task)
+		}
+		return task, nil
+	})
+
+	select {
+	case res := <-loaded:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// Callers may change the task they get, so each gets its own copy.
+		task := *res.Val.(*domain.Task)
+		return &task, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *TaskRepository) fill(ctx context.Context, key string, task *domain.Task) {
+	data, err := json.Marshal(task)
+	if err == nil {
+		err = r.backend.Set(ctx, key, data, r.ttl)
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Failed to cache task")
+	}
+}
+
+// evict drops the cached tasks with the given IDs.
+func (r *TaskRepository) evict(ctx context.Context, ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	r.epoch.Add(1)
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = taskKey(id)
+	}
+	if err := r.backend.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Strs("keys", keys).Msg("Failed to evict tasks from cache")
+	}
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	err := r.next.Update(ctx, task)
+	r.evict(ctx, task.ID)
+	return err
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	err := r.next.Delete(ctx, id)
+	r.evict(ctx, id)
+	return err
+}
+
+func (r *TaskRepository) AddUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	added, err := r.next.AddUser(ctx, id, relation, user)
+	r.evict(ctx, id)
+	return added, err
+}
+
+func (r *TaskRepository) RemoveUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	removed, err := r.next.RemoveUser(ctx, id, relation, user)
+	r.evict(ctx, id)
+	return removed, err
+}
+
+// AddHistory and History go straight to next: the history isn't part of
+// the cached task.
+func (r *TaskRepository) AddHistory(ctx context.Context, entry *domain.TaskHistoryEntry) error {
+	return r.next.AddHistory(ctx, entry)
+}
+
+func (r *TaskRepository) History(ctx context.Context, id uuid.UUID) ([]*domain.TaskHistoryEntry, error) {
+	return r.next.History(ctx, id)
+}
+
+// Rerank changes every task, so the whole cache is evicted afterwards.
+func (r *TaskRepository) Rerank(ctx context.Context, ranks func(n int) ([]string, error)) error {
+	err := r.next.Rerank(ctx, ranks)
+	r.evictAll(ctx)
+	return err
+}
+
+// evictAll drops every task in the repository from the cache. Tasks are
+// evicted in chunks as they are listed, so this is only worth it for rare,
+// table-wide changes.
+func (r *TaskRepository) evictAll(ctx context.Context) {
+	const chunk = 500
+
+	ids := make([]uuid.UUID, 0, chunk)
+	err := r.next.ForEach(context.WithoutCancel(ctx), func(task *domain.Task) error {
+		ids = append(ids, task.ID)
+		if len(ids) == chunk {
+			r.evict(ctx, ids...)
+			ids = ids[:0]
+		}
+		return nil
+	})
+	r.evict(ctx, ids...)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to list tasks to evict from cache")
+	}
+}
+
+func (r *TaskRepository) WithTx(ctx context.Context, fn func(repo repository.TaskRepository) error) error {
+	changed := &changeSet{}
+	err := r.next.WithTx(ctx, func(repo repository.TaskRepository) error {
+		return fn(&txTaskRepository{TaskRepository: repo, changed: changed})
+	})
+	// Evict after a rollback too: the transaction may have been committed
+	// even if reporting it failed.
+	if changed.everything() {
+		r.evictAll(ctx)
+	} else {
+		r.evict(ctx, changed.ids()...)
+	}
+	return err
+}
+
+func (r *TaskRepository) LockProject(ctx context.Context, projectID *uuid.UUID) error {
+	return r.next.LockProject(ctx, projectID)
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
+	return r.next.Create(ctx, task)
+}
+
+func (r *TaskRepository) List(ctx context.Context) ([]*domain.Task, error) {
+	return r.next.List(ctx)
+}
+
+func (r *TaskRepository) ForEach(ctx context.Context, fn func(task *domain.Task) error) error {
+	return r.next.ForEach(ctx, fn)
+}
+
+func (r *TaskRepository) Find(ctx context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error {
+	return r.next.Find(ctx, filter, fn)
+}
+
+func (r *TaskRepository) CreateMany(ctx context.Context, tasks []*domain.Task) error {
+	return r.next.CreateMany(ctx, tasks)
+}
+
+func (r *TaskRepository) Stats(ctx context.Context, now time.Time) (*domain.TaskStats, error) {
+	return r.next.Stats(ctx, now)
+}
+
+func (r *TaskRepository) LastRank(ctx context.Context) (string, error) {
+	return r.next.LastRank(ctx)
+}
+
+func (r *TaskRepository) NeighborRank(ctx context.Context, task *domain.Task, before bool, skip uuid.UUID) (string, error) {
+	return r.next.NeighborRank(ctx, task, before, skip)
+}
+
+func (r *TaskRepository) RankStats(ctx context.Context) (*domain.RankStats, error) {
+	return r.next.RankStats(ctx)
+}
+
+// txTaskRepository is handed to WithTx callbacks. It notes which tasks
+// change, so they can be evicted once the transaction is over, and reads
+// straight from the transaction.
+type txTaskRepository struct {
+	repository.TaskRepository
+	changed *changeSet
+}
+
+func (r *txTaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	r.changed.add(task.ID)
+	return r.TaskRepository.Update(ctx, task)
+}
+
+func (r *txTaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.changed.add(id)
+	return r.TaskRepository.Delete(ctx, id)
+}
+
+func (r *txTaskRepository) AddUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	r.changed.add(id)
+	return r.TaskRepository.AddUser(ctx, id, relation, user)
+}
+
+func (r *txTaskRepository) RemoveUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	r.changed.add(id)
+	return r.TaskRepository.RemoveUser(ctx, id, relation, user)
+}
+
+func (r *txTaskRepository) Rerank(ctx context.Context, ranks func(n int) ([]string, error)) error {
+	r.changed.addAll()
+	return r.TaskRepository.Rerank(ctx, ranks)
+}
+
+func (r *txTaskRepository) WithTx(ctx context.Context, fn func(repo repository.TaskRepository) error) error {
+	return r.TaskRepository.WithTx(ctx, func(repo repository.TaskRepository) error {
+		return fn(&txTaskRepository{TaskRepository: repo, changed: r.changed})
+	})
+}
+
+type changeSet struct {
+	mu  sync.Mutex
+	set map[uuid.UUID]struct{}
+	all bool
+}
+
+func (c *changeSet) add(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.set == nil {
+		c.set = make(map[uuid.UUID]struct{})
+	}
+	c.set[id] = struct{}{}
+}
+
+// addAll notes that every task may have changed.
+func (c *changeSet) addAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.all = true
+}
+
+func (c *changeSet) everything() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.all
+}
+
+func (c *changeSet) ids() []uuid.UUID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]uuid.UUID, 0, len(c.set))
+	for id := range c.set {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,398 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/memory"
+)
+
+// countingRepository counts the GetByID calls that reach it. While gate is
+// set, each of them waits for it to be closed.
+type countingRepository struct {
+	repository.TaskRepository
+	loads atomic.Int32
+	gate  chan struct{}
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
+	r.loads.Add(1)
+	if r.gate != nil {
+		<-r.gate
+	}
+	return r.TaskRepository.GetByID(ctx, id)
+}
+
+// observations records the results a TaskRepository reports.
+type observations struct {
+	mu      sync.Mutex
+	results []Result
+}
+
+func (o *observations) observe(result Result) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.results = append(o.results, result)
+}
+
+func (o *observations) count(result Result) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	n := 0
+	for _, r := range o.results {
+		if r == result {
+			n++
+		}
+	}
+	return n
+}
+
+type fixture struct {
+	next     *countingRepository
+	cached   *TaskRepository
+	observed *observations
+	tasks    []*domain.Task
+}
+
+// newFixture caches a memory repository holding n tasks in backend.
+func newFixture(t *testing.T, backend Backend, n int) *fixture {
+	t.Helper()
+
+	f := &fixture{
+		next:     &countingRepository{TaskRepository: memory.NewTaskRepository()},
+		observed: &observations{},
+	}
+	f.cached = NewTaskRepository(f.next, backend, time.Minute, WithObserver(f.observed.observe))
+
+	now := time.Now()
+	for i := range n {
+		task := &domain.Task{
+			ID:        uuid.New(),
+			Title:     "Task",
+			Status:    domain.TaskStatusTodo,
+			Priority:  domain.TaskPriorityP2,
+			Rank:      string(rune('a' + i)),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := f.cached.Create(context.Background(), task); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		f.tasks = append(f.tasks, task)
+	}
+	return f
+}
+
+func (f *fixture) get(t *testing.T, id uuid.UUID) *domain.Task {
+	t.Helper()
+	task, err := f.cached.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	return task
+}
+
+func TestGetByIDCaches(t *testing.T) {
+	f := newFixture(t, NewLRU(100), 1)
+	id := f.tasks[0].ID
+
+	first := f.get(t, id)
+	first.Title = "Changed by the caller"
+	second := f.get(t, id)
+
+	if loads := f.next.loads.Load(); loads != 1 {
+		t.Errorf("loads = %d, want 1", loads)
+	}
+	if f.observed.count(Miss) != 1 || f.observed.count(Hit) != 1 {
+		t.Errorf("observed %v, want one miss and one hit", f.observed.results)
+	}
+	if second.Title != "Task" {
+		t.Errorf("cached task was changed through a returned copy: %q", second.Title)
+	}
+}
+
+func TestGetByIDNotFoundIsNotCached(t *testing.T) {
+	f := newFixture(t, NewLRU(100), 0)
+	id := uuid.New()
+
+	for range 2 {
+		if _, err := f.cached.GetByID(context.Background(), id); !errors.Is(err, errs.ErrNotFound) {
+			t.Fatalf("GetByID = %v, want ErrNotFound", err)
+		}
+	}
+	if loads := f.next.loads.Load(); loads != 2 {
+		t.Errorf("loads = %d, want 2", loads)
+	}
+}
+
+// TestInvalidation caches every task, makes a change and checks that the
+// tasks it touched are loaded again while the others still come from the
+// cache.
+func TestInvalidation(t *testing.T) {
+	ctx := context.Background()
+	errRollback := errors.New("rollback")
+
+	tests := []struct {
+		name string
+		// change changes the fixture's tasks and returns the indexes of
+		// those it touched.
+		change func(t *testing.T, f *fixture) []int
+	}{
+		{"Update", func(t *testing.T, f *fixture) []int {
+			task := *f.tasks[0]
+			task.Title = "Updated"
+			if err := f.cached.Update(ctx, &task); err != nil {
+				t.Fatal(err)
+			}
+			return []int{0}
+		}},
+		{"Delete", func(t *testing.T, f *fixture) []int {
+			if err := f.cached.Delete(ctx, f.tasks[0].ID); err != nil {
+				t.Fatal(err)
+			}
+			return []int{0}
+		}},
+		{"AddUser", func(t *testing.T, f *fixture) []int {
+			if _, err := f.cached.AddUser(ctx, f.tasks[0].ID, domain.TaskRelationAssignee, "alice"); err != nil {
+				t.Fatal(err)
+			}
+			return []int{0}
+		}},
+		{"RemoveUser", func(t *testing.T, f *fixture) []int {
+			if _, err := f.cached.RemoveUser(ctx, f.tasks[0].ID, domain.TaskRelationAssignee, "alice"); err != nil {
+				t.Fatal(err)
+			}
+			return []int{0}
+		}},
+		{"Rerank", func(t *testing.T, f *fixture) []int {
+			if err := f.cached.Rerank(ctx, sequentialRanks); err != nil {
+				t.Fatal(err)
+			}
+			return []int{0, 1, 2}
+		}},
+		{"batch", func(t *testing.T, f *fixture) []int {
+			err := f.cached.WithTx(ctx, func(repo repository.TaskRepository) error {
+				task := *f.tasks[0]
+				task.Title = "Updated"
+				if err := repo.Update(ctx, &task); err != nil {
+					return err
+				}
+				return repo.Delete(ctx, f.tasks[1].ID)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return []int{0, 1}
+		}},
+		{"rolled back batch", func(t *testing.T, f *fixture) []int {
+			err := f.cached.WithTx(ctx, func(repo repository.TaskRepository) error {
+				task := *f.tasks[1]
+				task.Title = "Updated"
+				if err := repo.Update(ctx, &task); err != nil {
+					return err
+				}
+				return errRollback
+			})
+			if !errors.Is(err, errRollback) {
+				t.Fatalf("WithTx = %v, want the callback's error", err)
+			}
+			return []int{1}
+		}},
+		{"nested batch", func(t *testing.T, f *fixture) []int {
+			err := f.cached.WithTx(ctx, func(repo repository.TaskRepository) error {
+				return repo.WithTx(ctx, func(repo repository.TaskRepository) error {
+					_, err := repo.AddUser(ctx, f.tasks[2].ID, domain.TaskRelationWatcher, "bob")
+					return err
+				})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return []int{2}
+		}},
+		{"batch rerank", func(t *testing.T, f *fixture) []int {
+			err := f.cached.WithTx(ctx, func(repo repository.TaskRepository) error {
+				return repo.Rerank(ctx, sequentialRanks)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return []int{0, 1, 2}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, NewLRU(100), 3)
+			for _, task := range f.tasks {
+				f.get(t, task.ID)
+			}
+
+			touched := map[int]bool{}
+			for _, i := range tt.change(t, f) {
+				touched[i] = true
+			}
+
+			for i, task := range f.tasks {
+				before := f.next.loads.Load()
+				want, wantErr := f.next.TaskRepository.GetByID(ctx, task.ID)
+				got, err := f.cached.GetByID(ctx, task.ID)
+				loaded := f.next.loads.Load() > before
+
+				if loaded != touched[i] {
+					t.Errorf("task %d: loaded again = %v, want %v", i, loaded, touched[i])
+				}
+				if !errors.Is(err, wantErr) {
+					t.Errorf("task %d: GetByID error = %v, want %v", i, err, wantErr)
+				}
+				if err == nil && (got.Title != want.Title || len(got.Assignees) != len(want.Assignees) ||
+					len(got.Watchers) != len(want.Watchers) || got.Rank != want.Rank) {
+					t.Errorf("task %d: GetByID = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func sequentialRanks(n int) ([]string, error) {
+	ranks := make([]string, n)
+	for i := range ranks {
+		ranks[i] = "r" + string(rune('a'+i))
+	}
+	return ranks, nil
+}
+
+func TestConcurrentMissesShareOneLoad(t *testing.T) {
+	f := newFixture(t, NewLRU(100), 1)
+	id := f.tasks[0].ID
+	f.next.gate = make(chan struct{})
+
+	const callers = 20
+	var wg sync.WaitGroup
+	tasks := make([]*domain.Task, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tasks[i], errs[i] = f.cached.GetByID(context.Background(), id)
+		}()
+	}
+
+	// Every caller has missed once the observer saw them all; give the
+	// last ones a moment to join the load before letting it finish.
+	waitFor(t, func() bool { return f.observed.count(Miss) == callers })
+	time.Sleep(20 * time.Millisecond)
+	close(f.next.gate)
+	wg.Wait()
+
+	if loads := f.next.loads.Load(); loads != 1 {
+		t.Errorf("loads = %d, want 1 for %d concurrent misses", loads, callers)
+	}
+	for i := range callers {
+		if errs[i] != nil || tasks[i].ID != id {
+			t.Fatalf("caller %d: %v, %v", i, tasks[i], errs[i])
+		}
+		if i > 0 && tasks[i] == tasks[0] {
+			t.Fatalf("callers 0 and %d share a task value", i)
+		}
+	}
+}
+
+func TestWaitingCallerStopsWithItsContext(t *testing.T) {
+	f := newFixture(t, NewLRU(100), 1)
+	id := f.tasks[0].ID
+	f.next.gate = make(chan struct{})
+	defer close(f.next.gate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := f.cached.GetByID(ctx, id); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetByID = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+// TestEvictionDuringLoad changes a task while it is being loaded. The load
+// read the old version, so it must not be cached.
+func TestEvictionDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, NewLRU(100), 1)
+	id := f.tasks[0].ID
+	f.next.gate = make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.cached.GetByID(ctx, id)
+	}()
+	waitFor(t, func() bool { return f.next.loads.Load() == 1 })
+
+	task := *f.tasks[0]
+	task.Title = "Updated"
+	if err := f.cached.Update(ctx, &task); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	close(f.next.gate)
+	<-done
+
+	if got := f.get(t, id); got.Title != "Updated" {
+		t.Errorf("GetByID after the update = %q, want Updated", got.Title)
+	}
+}
+
+// failingBackend fails every call.
+type failingBackend struct{}
+
+var errBackend = errors.New("backend down")
+
+func (failingBackend) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errBackend
+}
+
+func (failingBackend) Set(context.Context, string, []byte, time.Duration) error {
+	return errBackend
+}
+
+func (failingBackend) Delete(context.Context, ...string) error {
+	return errBackend
+}
+
+func TestFailsOpen(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, failingBackend{}, 1)
+	id := f.tasks[0].ID
+
+	if got := f.get(t, id); got.ID != id {
+		t.Fatalf("GetByID = %s, want %s", got.ID, id)
+	}
+	task := *f.tasks[0]
+	task.Title = "Updated"
+	if err := f.cached.Update(ctx, &task); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := f.get(t, id); got.Title != "Updated" {
+		t.Errorf("GetByID = %q, want Updated", got.Title)
+	}
+	if n := f.observed.count(Error); n != 2 {
+		t.Errorf("observed %d errors, want 2", n)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
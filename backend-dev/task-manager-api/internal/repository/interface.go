@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
@@ -11,6 +12,149 @@ type TaskRepository interface {
 	Create(ctx context.Context, task *domain.Task) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error)
 	List(ctx context.Context) ([]*domain.Task, error)
+	// ForEach streams every task to fn in List order without loading them
+	// all into memory. Iteration stops at the first error returned by fn.
+	ForEach(ctx context.Context, fn func(task *domain.Task) error) error
+	// Find streams the tasks matching filter to fn, newest first like
+	// ForEach.
+	Find(ctx context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error
+	CreateMany(ctx context.Context, tasks []*domain.Task) error
+	// Stats counts tasks per status and the tasks that are not done but were
+	// due before now.
+	Stats(ctx context.Context, now time.Time) (*domain.TaskStats, error)
+	// Update stores every field of task except its assignees and watchers.
 	Update(ctx context.Context, task *domain.Task) error
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// AddUser makes user an assignee or a watcher of the task, per
+	// relation. It reports false when the user already was one, and fails
+	// with errs.ErrNotFound when there is no such task.
+	AddUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error)
+	// RemoveUser undoes AddUser. It reports false when the user wasn't one.
+	RemoveUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error)
+
+	// AddHistory records entry in the history of its task, which goes when
+	// the task is deleted. It fails with errs.ErrNotFound when there is no
+	// such task.
+	AddHistory(ctx context.Context, entry *domain.TaskHistoryEntry) error
+	// History returns the history of the task, oldest first.
+	History(ctx context.Context, id uuid.UUID) ([]*domain.TaskHistoryEntry, error)
+
+	// Rank order sorts tasks by rank, then created_at and id, so unranked
+	// tasks come first, oldest first.
+	//
+	// LastRank returns the highest rank, or "" when there are no ranked
+	// tasks.
+	LastRank(ctx context.Context) (string, error)
+	// NeighborRank returns the rank of the task following task in rank
+	// order, or preceding it when before is set, ignoring the task skip. It
+	// returns errs.ErrNotFound when task is at that end of the order. Inside
+	// a transaction the neighbor stays locked until it ends.
+	NeighborRank(ctx context.Context, task *domain.Task, before bool, skip uuid.UUID) (string, error)
+	RankStats(ctx context.Context) (*domain.RankStats, error)
+	// Rerank replaces every rank while keeping the rank order. ranks is
+	// called with the number of tasks and returns that many ascending keys.
+	// Other writers are blocked until it is done.
+	Rerank(ctx context.Context, ranks func(n int) ([]string, error)) error
+
+	// WithTx runs fn inside a transaction and passes it a repository bound to
+	// that transaction. The transaction is committed when fn returns nil and
+	// rolled back otherwise. Calling WithTx on a repository that is already
+	// bound to a transaction nests fn in a savepoint.
+	WithTx(ctx context.Context, fn func(repo TaskRepository) error) error
+	// LockProject makes other transactions locking the same project, or no
+	// project when projectID is nil, wait until this one ends. Outside a
+	// transaction it does nothing.
+	LockProject(ctx context.Context, projectID *uuid.UUID) error
+}
+
+type CalendarFeedRepository interface {
+	Create(ctx context.Context, feed *domain.CalendarFeed) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.CalendarFeed, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type ViewRepository interface {
+	Create(ctx context.Context, view *domain.View) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.View, error)
+	// List returns the views owned by owner or shared with team, by name.
+	// An empty owner or team matches nothing; with both empty every view
+	// is returned.
+	List(ctx context.Context, owner, team string) ([]*domain.View, error)
+	// ListOutdated returns the views last checked against a schema version
+	// older than version.
+	ListOutdated(ctx context.Context, version int) ([]*domain.View, error)
+	Update(ctx context.Context, view *domain.View) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type BoardRepository interface {
+	// Create stores the board and records its definition as version 1.
+	Create(ctx context.Context, board *domain.Board) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Board, error)
+	// List returns every board, by name.
+	List(ctx context.Context) ([]*domain.Board, error)
+	// Update stores board and records its definition as board.Version. It
+	// fails with errs.ErrConflict unless the stored board is at the
+	// version before.
+	Update(ctx context.Context, board *domain.Board) error
+	// Delete removes the board together with its versions.
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Versions returns every version of the board, oldest first.
+	Versions(ctx context.Context, id uuid.UUID) ([]*domain.BoardVersion, error)
+}
+
+type ProjectRepository interface {
+	// Create stores the project and, unless owner is nil, its first
+	// membership.
+	Create(ctx context.Context, project *domain.Project, owner *domain.Membership) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Project, error)
+	// List returns the projects with the given IDs, or every project when
+	// ids is nil, by name.
+	List(ctx context.Context, ids []uuid.UUID) ([]*domain.Project, error)
+	Update(ctx context.Context, project *domain.Project) error
+	// Delete removes the project together with its memberships.
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Members returns the memberships of the project, by user.
+	Members(ctx context.Context, id uuid.UUID) ([]*domain.Membership, error)
+	// Roles returns the user's role in every project they are a member of.
+	Roles(ctx context.Context, user string) (map[uuid.UUID]domain.ProjectRole, error)
+	// SetMember adds the membership or changes the role of an existing
+	// one. It fails with errs.ErrNotFound when there is no such project.
+	SetMember(ctx context.Context, membership *domain.Membership) error
+	// RemoveMember fails with errs.ErrNotFound when the user isn't a
+	// member of the project.
+	RemoveMember(ctx context.Context, id uuid.UUID, user string) error
+}
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *domain.APIToken) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.APIToken, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.APIToken, error)
+	// List returns the tokens of owner, newest first, revoked ones included.
+	List(ctx context.Context, owner string) ([]*domain.APIToken, error)
+	// Revoke marks the token revoked at at, unless it already is. It fails
+	// with errs.ErrNotFound when there is no such token.
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Touch records that the token was used at at.
+	Touch(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+type IdempotencyRepository interface {
+	// Acquire claims key for a new request. It returns true when the caller
+	// now owns the key: either the key was unused, the previous record
+	// expired before expiredBefore, or a request with the same fingerprint
+	// held the lock since before staleBefore and is presumed dead.
+	Acquire(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore, staleBefore time.Time) (bool, error)
+	Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *domain.IdempotencyRecord) error
+	Release(ctx context.Context, key string) error
+}
+
+// RateLimitStore keeps token buckets. Take must apply the bucket update for
+// key atomically, so concurrent requests can't spend the same token twice.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, policy domain.RateLimitPolicy) (domain.RateLimitResult, error)
 }
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+type APITokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[uuid.UUID]*domain.APIToken
+}
+
+func NewAPITokenRepository() *APITokenRepository {
+	return &APITokenRepository{tokens: make(map[uuid.UUID]*domain.APIToken)}
+}
+
+func (r *APITokenRepository) Create(ctx context.Context, token *domain.APIToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[token.ID] = copyAPIToken(token)
+	return nil
+}
+
+func (r *APITokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	return copyAPIToken(token), nil
+}
+
+func (r *APITokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return copyAPIToken(token), nil
+		}
+	}
+	return nil, errs.ErrNotFound
+}
+
+func (r *APITokenRepository) List(ctx context.Context, owner string) ([]*domain.APIToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tokens []*domain.APIToken
+	for _, token := range r.tokens {
+		if token.Owner == owner {
+			tokens = append(tokens, copyAPIToken(token))
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens, nil
+}
+
+func (r *APITokenRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok {
+		return errs.ErrNotFound
+	}
+	if token.RevokedAt == nil {
+		token.RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *APITokenRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.tokens[id]; ok {
+		token.LastUsedAt = &at
+	}
+	return nil
+}
+
+func copyAPIToken(token *domain.APIToken) *domain.APIToken {
+	c := *token
+	c.Scopes = slices.Clone(token.Scopes)
+	return &c
+}
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+type BoardRepository struct {
+	mu       sync.RWMutex
+	boards   map[uuid.UUID]*domain.Board
+	versions map[uuid.UUID][]*domain.BoardVersion
+}
+
+func NewBoardRepository() *BoardRepository {
+	return &BoardRepository{
+		boards:   make(map[uuid.UUID]*domain.Board),
+		versions: make(map[uuid.UUID][]*domain.BoardVersion),
+	}
+}
+
+func (r *BoardRepository) Create(ctx context.Context, board *domain.Board) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.boards[board.ID] = cloneBoard(board)
+	r.versions[board.ID] = []*domain.BoardVersion{boardVersion(board)}
+	return nil
+}
+
+func (r *BoardRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Board, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	board, ok := r.boards[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	return cloneBoard(board), nil
+}
+
+func (r *BoardRepository) List(ctx context.Context) ([]*domain.Board, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	boards := make([]*domain.Board, 0, len(r.boards))
+	for _, board := range r.boards {
+		boards = append(boards, cloneBoard(board))
+	}
+
+	slices.SortFunc(boards, func(a, b *domain.Board) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return boards, nil
+}
+
+func (r *BoardRepository) Update(ctx context.Context, board *domain.Board) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.boards[board.ID]
+	if !ok {
+		return errs.ErrNotFound
+	}
+	if stored.Version != board.Version-1 {
+		return errs.ErrConflict
+	}
+
+	updated := cloneBoard(board)
+	updated.CreatedAt = stored.CreatedAt
+	r.boards[board.ID] = updated
+	r.versions[board.ID] = append(r.versions[board.ID], boardVersion(updated))
+	return nil
+}
+
+func (r *BoardRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.boards[id]; !ok {
+		return errs.ErrNotFound
+	}
+	delete(r.boards, id)
+	delete(r.versions, id)
+	return nil
+}
+
+func (r *BoardRepository) Versions(ctx context.Context, id uuid.UUID) ([]*domain.BoardVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.versions[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+
+	versions := make([]*domain.BoardVersion, len(stored))
+	for i, version := range stored {
+		c := *version
+		c.Columns = cloneBoardColumns(version.Columns)
+		versions[i] = &c
+	}
+	return versions, nil
+}
+
+func boardVersion(board *domain.Board) *domain.BoardVersion {
+	return &domain.BoardVersion{
+		BoardID: board.ID,
+		Version: board.Version,
+		BoardDefinition: domain.BoardDefinition{
+			Name:    board.Name,
+			Columns: cloneBoardColumns(board.Columns),
+		},
+		CreatedAt: board.UpdatedAt,
+	}
+}
+
+// cloneBoard copies board deeply enough that callers can't change the
+// stored columns through it.
+func cloneBoard(board *domain.Board) *domain.Board {
+	c := *board
+	if board.ProjectID != nil {
+		id := *board.ProjectID
+		c.ProjectID = &id
+	}
+	c.Columns = cloneBoardColumns(board.Columns)
+	return &c
+}
+
+func cloneBoardColumns(columns []domain.BoardColumn) []domain.BoardColumn {
+	c := slices.Clone(columns)
+	for i := range c {
+		c[i].Statuses = slices.Clone(columns[i].Statuses)
+	}
+	return c
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+type CalendarFeedRepository struct {
+	mu    sync.RWMutex
+	feeds map[uuid.UUID]domain.CalendarFeed
+}
+
+func NewCalendarFeedRepository() *CalendarFeedRepository {
+	return &CalendarFeedRepository{feeds: make(map[uuid.UUID]domain.CalendarFeed)}
+}
+
+func (r *CalendarFeedRepository) Create(ctx context.Context, feed *domain.CalendarFeed) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.feeds[feed.ID] = *feed
+	return nil
+}
+
+func (r *CalendarFeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.CalendarFeed, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	feed, ok := r.feeds[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	return &feed, nil
+}
+
+func (r *CalendarFeedRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, feed := range r.feeds {
+		if feed.TokenHash == tokenHash {
+			return &feed, nil
+		}
+	}
+	return nil, errs.ErrNotFound
+}
+
+func (r *CalendarFeedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.feeds[id]; !ok {
+		return errs.ErrNotFound
+	}
+	delete(r.feeds, id)
+	return nil
+}
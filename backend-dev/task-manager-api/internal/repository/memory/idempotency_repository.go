@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+// IdempotencyRepository keeps idempotency records in process memory.
+// Expired records are replaced when their key is reused but otherwise kept
+// until restart.
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+}
+
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{records: make(map[string]*domain.IdempotencyRecord)}
+}
+
+func (r *IdempotencyRepository) Acquire(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.records[record.Key]; ok {
+		expired := existing.CreatedAt.Before(expiredBefore)
+		stale := !existing.Completed() && existing.LockedAt.Before(staleBefore) && existing.Fingerprint == record.Fingerprint
+		if !expired && !stale {
+			return false, nil
+		}
+	}
+
+	r.records[record.Key] = &domain.IdempotencyRecord{
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		LockedAt:    record.LockedAt,
+		CreatedAt:   record.CreatedAt,
+	}
+	return true, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.records[key]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	c := *record
+	return &c, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.records[record.Key]
+	if !ok || existing.Fingerprint != record.Fingerprint {
+		return nil
+	}
+	existing.StatusCode = record.StatusCode
+	existing.ContentType = record.ContentType
+	existing.ResponseBody = record.ResponseBody
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.records[key]; ok && !existing.Completed() {
+		delete(r.records, key)
+	}
+	return nil
+}
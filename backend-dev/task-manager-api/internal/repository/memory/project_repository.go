@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+type ProjectRepository struct {
+	mu       sync.RWMutex
+	projects map[uuid.UUID]*domain.Project
+	// members maps a project to its memberships by user.
+	members map[uuid.UUID]map[string]*domain.Membership
+}
+
+func NewProjectRepository() *ProjectRepository {
+	return &ProjectRepository{
+		projects: make(map[uuid.UUID]*domain.Project),
+		members:  make(map[uuid.UUID]map[string]*domain.Membership),
+	}
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, project *domain.Project, owner *domain.Membership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *project
+	r.projects[project.ID] = &stored
+	r.members[project.ID] = map[string]*domain.Membership{}
+	if owner != nil {
+		m := *owner
+		r.members[project.ID][owner.User] = &m
+	}
+	return nil
+}
+
+func (r *ProjectRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	project, ok := r.projects[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	c := *project
+	return &c, nil
+}
+
+func (r *ProjectRepository) List(ctx context.Context, ids []uuid.UUID) ([]*domain.Project, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make([]*domain.Project, 0, len(r.projects))
+	for _, project := range r.projects {
+		if ids != nil && !slices.Contains(ids, project.ID) {
+			continue
+		}
+		c := *project
+		projects = append(projects, &c)
+	}
+
+	slices.SortFunc(projects, func(a, b *domain.Project) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return projects, nil
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, project *domain.Project) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.projects[project.ID]
+	if !ok {
+		return errs.ErrNotFound
+	}
+	updated := *project
+	updated.CreatedAt = stored.CreatedAt
+	r.projects[project.ID] = &updated
+	return nil
+}
+
+func (r *ProjectRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.projects[id]; !ok {
+		return errs.ErrNotFound
+	}
+	delete(r.projects, id)
+	delete(r.members, id)
+	return nil
+}
+
+func (r *ProjectRepository) Members(ctx context.Context, id uuid.UUID) ([]*domain.Membership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members, ok := r.members[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+
+	memberships := make([]*domain.Membership, 0, len(members))
+	for _, m := range members {
+		c := *m
+		memberships = append(memberships, &c)
+	}
+	slices.SortFunc(memberships, func(a, b *domain.Membership) int {
+		return strings.Compare(a.User, b.User)
+	})
+	return memberships, nil
+}
+
+func (r *ProjectRepository) Roles(ctx context.Context, user string) (map[uuid.UUID]domain.ProjectRole, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	roles := make(map[uuid.UUID]domain.ProjectRole)
+	for id, members := range r.members {
+		if m, ok := members[user]; ok {
+			roles[id] = m.Role
+		}
+	}
+	return roles, nil
+}
+
+func (r *ProjectRepository) SetMember(ctx context.Context, membership *domain.Membership) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members, ok := r.members[membership.ProjectID]
+	if !ok {
+		return errs.ErrNotFound
+	}
+
+	m := *membership
+	// Like the upsert, an existing membership keeps its created_at.
+	if stored, ok := members[m.User]; ok {
+		m.CreatedAt = stored.CreatedAt
+	}
+	members[m.User] = &m
+	return nil
+}
+
+func (r *ProjectRepository) RemoveMember(ctx context.Context, id uuid.UUID, user string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members, ok := r.members[id]
+	if !ok {
+		return errs.ErrNotFound
+	}
+	if _, ok := members[user]; !ok {
+		return errs.ErrNotFound
+	}
+	delete(members, user)
+	return nil
+}
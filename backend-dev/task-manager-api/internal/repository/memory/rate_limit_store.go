@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+)
+
+// sweepEvery is how many calls to Take pass between sweeps of idle buckets.
+const sweepEvery = 1024
+
+// RateLimitStore keeps token buckets in process memory. Limits are per
+// instance; use the Postgres store to share them between replicas.
+type RateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*entry
+	calls   int
+}
+
+type entry struct {
+	bucket domain.TokenBucket
+	// idleAfter is when the bucket will be full again and can be dropped.
+	idleAfter time.Time
+}
+
+func NewRateLimitStore() *RateLimitStore {
+	return &RateLimitStore{buckets: make(map[string]*entry)}
+}
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, policy domain.RateLimitPolicy) (domain.RateLimitResult, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	e, ok := s.buckets[key]
+	if !ok {
+		e = &entry{bucket: domain.NewTokenBucket(policy, now)}
+		s.buckets[key] = e
+	}
+
+	result := e.bucket.Take(policy, now)
+	e.idleAfter = now.Add(result.ResetAfter)
+
+	return result, nil
+}
+
+// sweep drops buckets that have refilled completely; recreating them later
+// gives the same result.
+func (s *RateLimitStore) sweep(now time.Time) {
+	for key, e := range s.buckets {
+		if now.After(e.idleAfter) {
+			delete(s.buckets, key)
+		}
+	}
+}
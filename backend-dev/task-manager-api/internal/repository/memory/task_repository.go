@@ -0,0 +1,516 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+)
+
+// TaskRepository keeps tasks in process memory, for local development and
+// demos. Everything is lost on restart.
+//
+// Writes, including whole transactions, run one at a time. A transaction
+// works on a copy of all tasks that replaces the committed ones when it
+// succeeds, so it is only suited to small data sets.
+type TaskRepository struct {
+	store *taskStore
+
+	// tx is the working copy of a repository handed out by WithTx.
+	tx *taskTx
+}
+
+type taskStore struct {
+	// writeMu serializes writers and transactions; mu guards tasks and
+	// history.
+	writeMu sync.Mutex
+	mu      sync.RWMutex
+	tasks   map[uuid.UUID]*domain.Task
+	history map[uuid.UUID][]*domain.TaskHistoryEntry
+}
+
+type taskTx struct {
+	tasks   map[uuid.UUID]*domain.Task
+	history map[uuid.UUID][]*domain.TaskHistoryEntry
+}
+
+func NewTaskRepository() *TaskRepository {
+	return &TaskRepository{store: &taskStore{
+		tasks:   make(map[uuid.UUID]*domain.Task),
+		history: make(map[uuid.UUID][]*domain.TaskHistoryEntry),
+	}}
+}
+
+func (r *TaskRepository) WithTx(ctx context.Context, fn func(repo repository.TaskRepository) error) error {
+	if r.tx != nil {
+		// A savepoint: the nested copy replaces the outer one on success.
+		nested := &taskTx{tasks: maps.Clone(r.tx.tasks), history: maps.Clone(r.tx.history)}
+		if err := fn(&TaskRepository{store: r.store, tx: nested}); err != nil {
+			return err
+		}
+		r.tx.tasks, r.tx.history = nested.tasks, nested.history
+		return nil
+	}
+
+	r.store.writeMu.Lock()
+	defer r.store.writeMu.Unlock()
+
+	r.store.mu.RLock()
+	tx := &taskTx{tasks: maps.Clone(r.store.tasks), history: maps.Clone(r.store.history)}
+	r.store.mu.RUnlock()
+
+	if err := fn(&TaskRepository{store: r.store, tx: tx}); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	r.store.tasks, r.store.history = tx.tasks, tx.history
+	r.store.mu.Unlock()
+	return nil
+}
+
+// LockProject does nothing: transactions already run one at a time.
+func (r *TaskRepository) LockProject(ctx context.Context, projectID *uuid.UUID) error {
+	return nil
+}
+
+// read runs fn on the tasks visible to r.
+func (r *TaskRepository) read(fn func(tasks map[uuid.UUID]*domain.Task) error) error {
+	if r.tx != nil {
+		return fn(r.tx.tasks)
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return fn(r.store.tasks)
+}
+
+// history returns the task history visible to r. Only use it inside read or
+// write.
+func (r *TaskRepository) history() map[uuid.UUID][]*domain.TaskHistoryEntry {
+	if r.tx != nil {
+		return r.tx.history
+	}
+	return r.store.history
+}
+
+// write runs fn on the tasks visible to r, as a single write.
+func (r *TaskRepository) write(fn func(tasks map[uuid.UUID]*domain.Task) error) error {
+	if r.tx != nil {
+		return fn(r.tx.tasks)
+	}
+	r.store.writeMu.Lock()
+	defer r.store.writeMu.Unlock()
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	return fn(r.store.tasks)
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
+	return r.write(func(tasks map[uuid.UUID]*domain.Task) error {
+		return insert(tasks, task)
+	})
+}
+
+func insert(tasks map[uuid.UUID]*domain.Task, task *domain.Task) error {
+	if _, ok := tasks[task.ID]; ok {
+		return fmt.Errorf("task %s already exists", task.ID)
+	}
+	tasks[task.ID] = cloneTask(task)
+	return nil
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
+	var task *domain.Task
+	err := r.read(func(tasks map[uuid.UUID]*domain.Task) error {
+		stored, ok := tasks[id]
+		if !ok {
+			return errs.ErrNotFound
+		}
+		task = cloneTask(stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (r *TaskRepository) List(ctx context.Context) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+
+	err := r.ForEach(ctx, func(task *domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *TaskRepository) ForEach(ctx context.Context, fn func(task *domain.Task) error) error {
+	return r.Find(ctx, domain.TaskFilter{}, fn)
+}
+
+// Find matches filter the way the Postgres repository does. The matches are
+// copied before fn runs, so fn may call back into the repository.
+func (r *TaskRepository) Find(ctx context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error {
+	var matches []*domain.Task
+	err := r.read(func(tasks map[uuid.UUID]*domain.Task) error {
+		for _, task := range tasks {
+			if matchesFilter(task, filter) {
+				matches = append(matches, cloneTask(task))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	compare := compareNewestFirst
+	if filter.RankOrder {
+		compare = compareRankOrder
+	}
+	slices.SortFunc(matches, func(a, b *domain.Task) int {
+		return compare(a.Cursor(), b.Cursor())
+	})
+	if filter.Limit > 0 && len(matches) > filter.Limit {
+		matches = matches[:filter.Limit]
+	}
+
+	for _, task := range matches {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchesFilter(task *domain.Task, filter domain.TaskFilter) bool {
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, task.Status) {
+		return false
+	}
+	if len(filter.IDs) > 0 && !slices.Contains(filter.IDs, task.ID) {
+		return false
+	}
+	if filter.HasDueDate && task.DueDate == nil {
+		return false
+	}
+	if filter.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *filter.ProjectID) {
+		return false
+	}
+	if filter.NoProject && task.ProjectID != nil {
+		return false
+	}
+	if filter.RestrictProjects && (task.ProjectID == nil || !slices.Contains(filter.Projects, *task.ProjectID)) {
+		return false
+	}
+	if filter.Assignee != "" && !slices.Contains(task.Assignees, filter.Assignee) {
+		return false
+	}
+	if filter.Unassigned && len(task.Assignees) > 0 {
+		return false
+	}
+	if filter.Search != "" {
+		search := strings.ToLower(filter.Search)
+		if !strings.Contains(strings.ToLower(task.Title), search) &&
+			!strings.Contains(strings.ToLower(task.Description), search) {
+			return false
+		}
+	}
+	if filter.After != nil {
+		compare := compareNewestFirst
+		if filter.RankOrder {
+			compare = compareRankOrder
+		}
+		if compare(task.Cursor(), *filter.After) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNewestFirst orders cursors by created_at DESC, id DESC. UUIDs
+// compare byte by byte, as in Postgres.
+func compareNewestFirst(a, b domain.TaskCursor) int {
+	if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(b.ID[:], a.ID[:])
+}
+
+// CreateMany inserts all tasks or, when one of them fails, none.
+func (r *TaskRepository) CreateMany(ctx context.Context, tasks []*domain.Task) error {
+	return r.WithTx(ctx, func(repo repository.TaskRepository) error {
+		tx := repo.(*TaskRepository).tx
+		for _, task := range tasks {
+			if err := insert(tx.tasks, task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TaskRepository) Stats(ctx context.Context, now time.Time) (*domain.TaskStats, error) {
+	stats := &domain.TaskStats{ByStatus: map[domain.TaskStatus]int{}}
+
+	err := r.read(func(tasks map[uuid.UUID]*domain.Task) error {
+		for _, task := range tasks {
+			stats.ByStatus[task.Status]++
+			if task.Status != domain.TaskStatusDone && task.DueDate != nil && task.DueDate.Before(now) {
+				stats.Overdue++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	return r.write(func(tasks map[uuid.UUID]*domain.Task) error {
+		stored, ok := tasks[task.ID]
+		if !ok {
+			return errs.ErrNotFound
+		}
+		// Like the UPDATE statement, leave created_at, the project and the
+		// people alone.
+		updated := cloneTask(task)
+		updated.CreatedAt = stored.CreatedAt
+		updated.ProjectID = stored.ProjectID
+		updated.Assignees = slices.Clone(stored.Assignees)
+		updated.Watchers = slices.Clone(stored.Watchers)
+		tasks[task.ID] = updated
+		return nil
+	})
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.write(func(tasks map[uuid.UUID]*domain.Task) error {
+		if _, ok := tasks[id]; !ok {
+			return errs.ErrNotFound
+		}
+		delete(tasks, id)
+		delete(r.history(), id)
+		return nil
+	})
+}
+
+func (r *TaskRepository) AddHistory(ctx context.Context, entry *domain.TaskHistoryEntry) error {
+	return r.write(func(tasks map[uuid.UUID]*domain.Task) error {
+		if _, ok := tasks[entry.TaskID]; !ok {
+			return errs.ErrNotFound
+		}
+		c := *entry
+		history := r.history()
+		// Clipped, so the copies WithTx keeps never share the new entry.
+		history[entry.TaskID] = append(slices.Clip(history[entry.TaskID]), &c)
+		return nil
+	})
+}
+
+func (r *TaskRepository) History(ctx context.Context, id uuid.UUID) ([]*domain.TaskHistoryEntry, error) {
+	var entries []*domain.TaskHistoryEntry
+	err := r.read(func(tasks map[uuid.UUID]*domain.Task) error {
+		if _, ok := tasks[id]; !ok {
+			return errs.ErrNotFound
+		}
+		stored := r.history()[id]
+		entries = make([]*domain.TaskHistoryEntry, len(stored))
+		for i, entry := range stored {
+			c := *entry
+			entries[i] = &c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *TaskRepository) AddUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	return r.changeUsers(id, relation, func(users []string) []string {
+		i, found := slices.BinarySearch(users, user)
+		if found {
+			return nil
+		}
+		return slices.Insert(slices.Clone(users), i, user)
+	})
+}
+
+func (r *TaskRepository) RemoveUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	return r.changeUsers(id, relation, func(users []string) []string {
+		i, found := slices.BinarySearch(users, user)
+		if !found {
+			return nil
+		}
+		// An empty, non-nil list still counts as a change.
+		return slices.Delete(slices.Clone(users), i, i+1)
+	})
+}
+
+// changeUsers replaces the task's users of the given relation with what
+// change returns for them, unless it returns nil.
+func (r *TaskRepository) changeUsers(id uuid.UUID, relation domain.TaskRelation, change func(users []string) []string) (bool, error) {
+	changed := false
+	err := r.write(func(tasks map[uuid.UUID]*domain.Task) error {
+		stored, ok := tasks[id]
+		if !ok {
+			return errs.ErrNotFound
+		}
+
+		// Stored tasks may be shared with the committed state, so replace
+		// the task rather than changing it in place.
+		updated := cloneTask(stored)
+		users := &updated.Assignees
+		if relation == domain.TaskRelationWatcher {
+			users = &updated.Watchers
+		}
+		next := change(*users)
+		if next == nil {
+			return nil
+		}
+		if len(next) == 0 {
+			next = nil
+		}
+
+		*users = next
+		tasks[id] = updated
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+func (r *TaskRepository) LastRank(ctx context.Context) (string, error) {
+	var last string
+	err := r.read(func(tasks map[uuid.UUID]*domain.Task) error {
+		for _, task := range tasks {
+			last = max(last, task.Rank)
+		}
+		return nil
+	})
+	return last, err
+}
+
+func (r *TaskRepository) NeighborRank(ctx context.Context, task *domain.Task, before bool, skip uuid.UUID) (string, error) {
+	// The neighbor is the closest task in direction dir.
+	dir := 1
+	if before {
+		dir = -1
+	}
+
+	var neighbor *domain.Task
+	err := r.read(func(tasks map[uuid.UUID]*domain.Task) error {
+		for _, t := range tasks {
+			if t.ID == skip || dir*compareRankOrder(t.Cursor(), task.Cursor()) <= 0 {
+				continue
+			}
+			if neighbor == nil || dir*compareRankOrder(t.Cursor(), neighbor.Cursor()) < 0 {
+				neighbor = t
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if neighbor == nil {
+		return "", errs.ErrNotFound
+	}
+	return neighbor.Rank, nil
+}
+
+func (r *TaskRepository) RankStats(ctx context.Context) (*domain.RankStats, error) {
+	stats := &domain.RankStats{}
+	err := r.read(func(tasks map[uuid.UUID]*domain.Task) error {
+		for _, task := range tasks {
+			stats.MaxLength = max(stats.MaxLength, len(task.Rank))
+			if task.Rank == "" {
+				stats.Unranked++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *TaskRepository) Rerank(ctx context.Context, ranks func(n int) ([]string, error)) error {
+	return r.write(func(tasks map[uuid.UUID]*domain.Task) error {
+		ordered := make([]*domain.Task, 0, len(tasks))
+		for _, task := range tasks {
+			ordered = append(ordered, task)
+		}
+		slices.SortFunc(ordered, func(a, b *domain.Task) int {
+			return compareRankOrder(a.Cursor(), b.Cursor())
+		})
+
+		keys, err := ranks(len(ordered))
+		if err != nil {
+			return err
+		}
+		if len(keys) != len(ordered) {
+			return fmt.Errorf("got %d ranks for %d tasks", len(keys), len(ordered))
+		}
+
+		// Stored tasks may be shared with the committed state, so replace
+		// them rather than changing them in place.
+		for i, task := range ordered {
+			updated := cloneTask(task)
+			updated.Rank = keys[i]
+			tasks[task.ID] = updated
+		}
+		return nil
+	})
+}
+
+// compareRankOrder orders cursors by rank, created_at and id, like the rank
+// order of the Postgres repository.
+func compareRankOrder(a, b domain.TaskCursor) int {
+	if c := strings.Compare(a.Rank, b.Rank); c != 0 {
+		return c
+	}
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// cloneTask copies task so callers can't change stored tasks through the
+// pointers they pass in or get back.
+func cloneTask(task *domain.Task) *domain.Task {
+	c := *task
+	if task.ProjectID != nil {
+		id := *task.ProjectID
+		c.ProjectID = &id
+	}
+	if task.DueDate != nil {
+		due := *task.DueDate
+		c.DueDate = &due
+	}
+	c.Assignees = slices.Clone(task.Assignees)
+	c.Watchers = slices.Clone(task.Watchers)
+	return &c
+}
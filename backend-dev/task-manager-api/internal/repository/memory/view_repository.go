@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+type ViewRepository struct {
+	mu    sync.RWMutex
+	views map[uuid.UUID]*domain.View
+}
+
+func NewViewRepository() *ViewRepository {
+	return &ViewRepository{views: make(map[uuid.UUID]*domain.View)}
+}
+
+func (r *ViewRepository) Create(ctx context.Context, view *domain.View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.views[view.ID] = cloneView(view)
+	return nil
+}
+
+func (r *ViewRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.View, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	view, ok := r.views[id]
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+	return cloneView(view), nil
+}
+
+func (r *ViewRepository) List(ctx context.Context, owner, team string) ([]*domain.View, error) {
+	views := r.filter(func(view *domain.View) bool {
+		return (owner == "" && team == "") ||
+			(owner != "" && view.Owner == owner) ||
+			(team != "" && view.Team == team)
+	})
+
+	slices.SortFunc(views, func(a, b *domain.View) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return views, nil
+}
+
+func (r *ViewRepository) ListOutdated(ctx context.Context, version int) ([]*domain.View, error) {
+	return r.filter(func(view *domain.View) bool {
+		return view.SchemaVersion < version
+	}), nil
+}
+
+func (r *ViewRepository) Update(ctx context.Context, view *domain.View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.views[view.ID]
+	if !ok {
+		return errs.ErrNotFound
+	}
+
+	updated := cloneView(view)
+	updated.Owner = stored.Owner
+	updated.CreatedAt = stored.CreatedAt
+	r.views[view.ID] = updated
+	return nil
+}
+
+func (r *ViewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.views[id]; !ok {
+		return errs.ErrNotFound
+	}
+	delete(r.views, id)
+	return nil
+}
+
+func (r *ViewRepository) filter(match func(view *domain.View) bool) []*domain.View {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var views []*domain.View
+	for _, view := range r.views {
+		if match(view) {
+			views = append(views, cloneView(view))
+		}
+	}
+	return views
+}
+
+// cloneView copies view deeply enough that callers can't change the stored
+// definition through it.
+func cloneView(view *domain.View) *domain.View {
+	c := *view
+	c.Filter.Statuses = slices.Clone(view.Filter.Statuses)
+	c.Sort = slices.Clone(view.Sort)
+	c.Columns = slices.Clone(view.Columns)
+	return &c
+}
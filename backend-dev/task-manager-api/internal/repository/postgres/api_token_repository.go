@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+const apiTokenColumns = `id, name, owner, prefix, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+type APITokenRepository struct {
+	db dbtx
+}
+
+func NewAPITokenRepository(db *sql.DB) *APITokenRepository {
+	return &APITokenRepository{db: instrumentedDB{db}}
+}
+
+func (r *APITokenRepository) Create(ctx context.Context, token *domain.APIToken) error {
+	scopes, err := json.Marshal(token.Scopes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO api_tokens (id, name, owner, prefix, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.Name,
+		token.Owner,
+		token.Prefix,
+		token.TokenHash,
+		scopes,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+func (r *APITokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIToken, error) {
+	return r.getOne(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE id = $1`, id)
+}
+
+func (r *APITokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	return r.getOne(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = $1`, tokenHash)
+}
+
+func (r *APITokenRepository) List(ctx context.Context, owner string) ([]*domain.APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens WHERE owner = $1 ORDER BY created_at DESC, id`
+
+	rows, err := r.db.QueryContext(ctx, query, owner)
+	if err != nil {
+		return nil, err
+	}
+	return scanAPITokens(rows)
+}
+
+func (r *APITokenRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, $1) WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, at, id)
+	if err != nil {
+		return err
+	}
+	return expectRow(result)
+}
+
+func (r *APITokenRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+func (r *APITokenRepository) getOne(ctx context.Context, query string, arg any) (*domain.APIToken, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := scanAPITokens(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errs.ErrNotFound
+	}
+	return tokens[0], nil
+}
+
+func scanAPITokens(rows sqlRows) ([]*domain.APIToken, error) {
+	defer rows.Close()
+
+	var tokens []*domain.APIToken
+	for rows.Next() {
+		var token domain.APIToken
+		var scopes []byte
+		var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+		if err := rows.Scan(
+			&token.ID,
+			&token.Name,
+			&token.Owner,
+			&token.Prefix,
+			&token.TokenHash,
+			&scopes,
+			&expiresAt,
+			&lastUsedAt,
+			&revokedAt,
+			&token.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(scopes, &token.Scopes); err != nil {
+			return nil, fmt.Errorf("decoding scopes of API token %s: %w", token.ID, err)
+		}
+		if expiresAt.Valid {
+			token.ExpiresAt = &expiresAt.Time
+		}
+		if lastUsedAt.Valid {
+			token.LastUsedAt = &lastUsedAt.Time
+		}
+		if revokedAt.Valid {
+			token.RevokedAt = &revokedAt.Time
+		}
+
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, rows.Err()
+}
@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+const boardColumns = `id, project_id, name, columns, version, created_at, updated_at`
+
+type BoardRepository struct {
+	db dbtx
+}
+
+func NewBoardRepository(db *sql.DB) *BoardRepository {
+	return &BoardRepository{db: instrumentedDB{db}}
+}
+
+func (r *BoardRepository) Create(ctx context.Context, board *domain.Board) error {
+	columns, err := json.Marshal(board.Columns)
+	if err != nil {
+		return err
+	}
+
+	// One statement, so the board never exists without its first version.
+	query := `
+		WITH board AS (
+			INSERT INTO boards (id, project_id, name, columns, version, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id
+		)
+		INSERT INTO board_versions (board_id, version, name, columns, created_at)
+		SELECT id, $5, $3, $4, $7 FROM board
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		board.ID,
+		board.ProjectID,
+		board.Name,
+		columns,
+		board.Version,
+		board.CreatedAt,
+		board.UpdatedAt,
+	)
+	return err
+}
+
+func (r *BoardRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Board, error) {
+	query := `SELECT ` + boardColumns + ` FROM boards WHERE id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	boards, err := scanBoards(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(boards) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	return boards[0], nil
+}
+
+func (r *BoardRepository) List(ctx context.Context) ([]*domain.Board, error) {
+	query := `SELECT ` + boardColumns + ` FROM boards ORDER BY name, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return scanBoards(rows)
+}
+
+func (r *BoardRepository) Update(ctx context.Context, board *domain.Board) error {
+	columns, err := json.Marshal(board.Columns)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		WITH board AS (
+			UPDATE boards
+			SET name = $1, columns = $2, version = $3, updated_at = $4
+			WHERE id = $5 AND version = $3 - 1
+			RETURNING id
+		)
+		INSERT INTO board_versions (board_id, version, name, columns, created_at)
+		SELECT id, $3, $1, $2, $4 FROM board
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		board.Name,
+		columns,
+		board.Version,
+		board.UpdatedAt,
+		board.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		// Either the board is gone or someone else updated it first.
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM boards WHERE id = $1)`, board.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errs.ErrNotFound
+		}
+		return errs.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *BoardRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM boards WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *BoardRepository) Versions(ctx context.Context, id uuid.UUID) ([]*domain.BoardVersion, error) {
+	query := `
+		SELECT board_id, version, name, columns, created_at
+		FROM board_versions
+		WHERE board_id = $1
+		ORDER BY version
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*domain.BoardVersion
+	for rows.Next() {
+		var version domain.BoardVersion
+		var columns []byte
+
+		if err := rows.Scan(&version.BoardID, &version.Version, &version.Name, &columns, &version.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(columns, &version.Columns); err != nil {
+			return nil, fmt.Errorf("decoding columns of board %s version %d: %w", id, version.Version, err)
+		}
+
+		versions = append(versions, &version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Every board has at least its first version.
+	if len(versions) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	return versions, nil
+}
+
+func scanBoards(rows sqlRows) ([]*domain.Board, error) {
+	defer rows.Close()
+
+	var boards []*domain.Board
+	for rows.Next() {
+		var board domain.Board
+		var projectID uuid.NullUUID
+		var columns []byte
+
+		if err := rows.Scan(
+			&board.ID,
+			&projectID,
+			&board.Name,
+			&columns,
+			&board.Version,
+			&board.CreatedAt,
+			&board.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if projectID.Valid {
+			board.ProjectID = &projectID.UUID
+		}
+		if err := json.Unmarshal(columns, &board.Columns); err != nil {
+			return nil, fmt.Errorf("decoding columns of board %s: %w", board.ID, err)
+		}
+
+		boards = append(boards, &board)
+	}
+
+	return boards, rows.Err()
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+type CalendarFeedRepository struct {
+	db dbtx
+}
+
+func NewCalendarFeedRepository(db *sql.DB) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: instrumentedDB{db}}
+}
+
+func (r *CalendarFeedRepository) Create(ctx context.Context, feed *domain.CalendarFeed) error {
+	query := `
+		INSERT INTO calendar_feeds (id, owner, token_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, feed.ID, feed.Owner, feed.TokenHash, feed.CreatedAt)
+	return err
+}
+
+func (r *CalendarFeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.CalendarFeed, error) {
+	query := `
+		SELECT id, owner, token_hash, created_at
+		FROM calendar_feeds
+		WHERE id = $1
+	`
+
+	var feed domain.CalendarFeed
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&feed.ID,
+		&feed.Owner,
+		&feed.TokenHash,
+		&feed.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (r *CalendarFeedRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	query := `
+		SELECT id, owner, token_hash, created_at
+		FROM calendar_feeds
+		WHERE token_hash = $1
+	`
+
+	var feed domain.CalendarFeed
+
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&feed.ID,
+		&feed.Owner,
+		&feed.TokenHash,
+		&feed.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+func (r *CalendarFeedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM calendar_feeds WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
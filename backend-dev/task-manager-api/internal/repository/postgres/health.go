@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SchemaVersion is the latest version recorded in schema_migrations by
+// migrations/init.sql that this build relies on.
+const SchemaVersion = 11
+
+// Ping checks that the database accepts connections.
+func Ping(db *sql.DB) func(ctx context.Context) error {
+	return db.PingContext
+}
+
+// CheckSchema fails when the database has not been migrated to at least
+// SchemaVersion.
+func CheckSchema(db *sql.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var version sql.NullInt64
+		if err := db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+			return err
+		}
+		if version.Int64 < SchemaVersion {
+			return fmt.Errorf("schema version %d, want %d", version.Int64, SchemaVersion)
+		}
+		return nil
+	}
+}
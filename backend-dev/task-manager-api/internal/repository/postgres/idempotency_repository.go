@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+type IdempotencyRepository struct {
+	db dbtx
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: instrumentedDB{db}}
+}
+
+func (r *IdempotencyRepository) Acquire(ctx context.Context, record *domain.IdempotencyRecord, expiredBefore, staleBefore time.Time) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (key, fingerprint, locked_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			locked_at = EXCLUDED.locked_at,
+			created_at = EXCLUDED.created_at
+		WHERE idempotency_keys.created_at < $5
+			OR (idempotency_keys.status_code IS NULL
+				AND idempotency_keys.locked_at < $6
+				AND idempotency_keys.fingerprint = EXCLUDED.fingerprint)
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		record.Key,
+		record.Fingerprint,
+		record.LockedAt,
+		record.CreatedAt,
+		expiredBefore,
+		staleBefore,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	query := `
+		SELECT key, fingerprint, status_code, content_type, response_body, locked_at, created_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+
+	var record domain.IdempotencyRecord
+	var statusCode sql.NullInt32
+	var contentType sql.NullString
+
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&statusCode,
+		&contentType,
+		&record.ResponseBody,
+		&record.LockedAt,
+		&record.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, err
+	}
+
+	record.StatusCode = int(statusCode.Int32)
+	record.ContentType = contentType.String
+
+	return &record, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record *domain.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, content_type = $2, response_body = $3
+		WHERE key = $4 AND fingerprint = $5
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		record.StatusCode,
+		record.ContentType,
+		record.ResponseBody,
+		record.Key,
+		record.Fingerprint,
+	)
+
+	return err
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE key = $1 AND status_code IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, key)
+	return err
+}
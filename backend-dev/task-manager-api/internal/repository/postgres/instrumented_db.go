@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/postgres")
+
+// sqlDB is what instrumentedDB wraps: *sql.DB or *sql.Tx.
+type sqlDB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// instrumentedDB wraps every statement in a client span carrying the SQL
+// text and logs it on the request-scoped logger from ctx: at debug level
+// normally and at error level when it fails.
+type instrumentedDB struct {
+	db sqlDB
+}
+
+func (i instrumentedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span, start := startQuery(ctx, query)
+	result, err := i.db.ExecContext(ctx, query, args...)
+	endQuery(ctx, span, query, start, err)
+	return result, err
+}
+
+// QueryContext ends the span when the rows are closed, so it covers reading
+// them as well as running the statement.
+func (i instrumentedDB) QueryContext(ctx context.Context, query string, args ...any) (sqlRows, error) {
+	ctx, span, start := startQuery(ctx, query)
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		endQuery(ctx, span, query, start, err)
+		return nil, err
+	}
+	return &instrumentedRows{Rows: rows, end: func(err error) { endQuery(ctx, span, query, start, err) }}, nil
+}
+
+// instrumentedRows ends the span of its query on the first Close, recording
+// any error met while reading the rows.
+type instrumentedRows struct {
+	*sql.Rows
+	end    func(err error)
+	closed bool
+}
+
+func (r *instrumentedRows) Close() error {
+	err := r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		r.end(errors.Join(r.Rows.Err(), err))
+	}
+	return err
+}
+
+// QueryRowContext defers errors to Scan, so only the statement is recorded.
+func (i instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span, start := startQuery(ctx, query)
+	row := i.db.QueryRowContext(ctx, query, args...)
+	endQuery(ctx, span, query, start, nil)
+	return row
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span, time.Time) {
+	statement := strings.Join(strings.Fields(query), " ")
+
+	ctx, span := tracer.Start(ctx, spanName(statement),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			attribute.String("db.statement", statement),
+		),
+	)
+
+	return ctx, span, time.Now()
+}
+
+func endQuery(ctx context.Context, span trace.Span, query string, start time.Time, err error) {
+	log := zerolog.Ctx(ctx)
+
+	event := log.Debug()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		event = log.Error().Err(err)
+	}
+	span.End()
+
+	event.
+		Str("query", strings.Join(strings.Fields(query), " ")).
+		Dur("latency", time.Since(start)).
+		Msg("SQL query")
+}
+
+// spanName uses the SQL verb and, where easy to find, the table, e.g.
+// "SELECT tasks".
+func spanName(statement string) string {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return "SQL"
+	}
+
+	verb := strings.ToUpper(fields[0])
+	for i, f := range fields[:len(fields)-1] {
+		switch strings.ToUpper(f) {
+		case "FROM", "INTO", "UPDATE":
+			return verb + " " + fields[i+1]
+		}
+	}
+	return verb
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeDriver answers every query with rows 1 to 3, except that it fails
+// "SELECT broken".
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if query == "SELECT broken" {
+		return nil, errors.New("syntax error")
+	}
+	return &fakeRows{}, nil
+}
+
+type fakeRows struct{ n int64 }
+
+func (*fakeRows) Columns() []string { return []string{"n"} }
+func (*fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.n == 3 {
+		return io.EOF
+	}
+	r.n++
+	dest[0] = r.n
+	return nil
+}
+
+// spans records the spans of every test: the package tracer only picks up
+// the first provider set.
+var spans = tracetest.NewSpanRecorder()
+
+func init() {
+	sql.Register("postgres-test-fake", fakeDriver{})
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+}
+
+// endedSince returns the spans ended after the first n.
+func endedSince(n int) []sdktrace.ReadOnlySpan {
+	return spans.Ended()[n:]
+}
+
+func TestQueryContextSpanCoversReadingRows(t *testing.T) {
+	before := len(spans.Ended())
+	db, err := sql.Open("postgres-test-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := instrumentedDB{db}.QueryContext(context.Background(), "SELECT n FROM numbers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum int64
+	for rows.Next() {
+		if n := len(endedSince(before)); n != 0 {
+			t.Fatalf("%d spans ended while reading rows", n)
+		}
+		var n int64
+		if err := rows.Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		sum += n
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sum != 6 {
+		t.Errorf("sum = %d, want 6", sum)
+	}
+	ended := endedSince(before)
+	if len(ended) != 1 {
+		t.Fatalf("%d spans ended, want 1", len(ended))
+	}
+	if span := ended[0]; span.Name() != "SELECT numbers" || span.Status().Code == codes.Error {
+		t.Errorf("span %q, status %v", span.Name(), span.Status())
+	}
+}
+
+func TestQueryContextFailureEndsSpan(t *testing.T) {
+	before := len(spans.Ended())
+	db, err := sql.Open("postgres-test-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := instrumentedDB{db}.QueryContext(context.Background(), "SELECT broken")
+	if err == nil || rows != nil {
+		t.Fatalf("QueryContext = %v, %v, want an error and no rows", rows, err)
+	}
+
+	ended := endedSince(before)
+	if len(ended) != 1 || ended[0].Status().Code != codes.Error {
+		t.Fatalf("ended spans = %v, want one failed span", ended)
+	}
+}
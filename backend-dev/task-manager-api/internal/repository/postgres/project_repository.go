@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+const projectColumns = `id, name, created_at, updated_at`
+
+type ProjectRepository struct {
+	db dbtx
+}
+
+func NewProjectRepository(db *sql.DB) *ProjectRepository {
+	return &ProjectRepository{db: instrumentedDB{db}}
+}
+
+func (r *ProjectRepository) Create(ctx context.Context, project *domain.Project, owner *domain.Membership) error {
+	if owner == nil {
+		query := `INSERT INTO projects (id, name, created_at, updated_at) VALUES ($1, $2, $3, $4)`
+		_, err := r.db.ExecContext(ctx, query, project.ID, project.Name, project.CreatedAt, project.UpdatedAt)
+		return err
+	}
+
+	// One statement, so the project never exists without its owner.
+	query := `
+		WITH project AS (
+			INSERT INTO projects (id, name, created_at, updated_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		)
+		INSERT INTO project_members (project_id, user_id, role, created_at, updated_at)
+		SELECT id, $5, $6, $7, $7 FROM project
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		project.ID,
+		project.Name,
+		project.CreatedAt,
+		project.UpdatedAt,
+		owner.User,
+		owner.Role,
+		owner.CreatedAt,
+	)
+	return err
+}
+
+func (r *ProjectRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE id = $1`
+
+	var project domain.Project
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (r *ProjectRepository) List(ctx context.Context, ids []uuid.UUID) ([]*domain.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects`
+	var args []any
+	if ids != nil {
+		strs := make([]string, len(ids))
+		for i, id := range ids {
+			strs[i] = id.String()
+		}
+		query += ` WHERE id = ANY($1::uuid[])`
+		args = append(args, strs)
+	}
+	query += ` ORDER BY name, id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*domain.Project
+	for rows.Next() {
+		var project domain.Project
+		if err := rows.Scan(&project.ID, &project.Name, &project.CreatedAt, &project.UpdatedAt); err != nil {
+			return nil, err
+		}
+		projects = append(projects, &project)
+	}
+
+	return projects, rows.Err()
+}
+
+func (r *ProjectRepository) Update(ctx context.Context, project *domain.Project) error {
+	query := `UPDATE projects SET name = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, project.Name, project.UpdatedAt, project.ID)
+	if err != nil {
+		return err
+	}
+	return expectRow(result)
+}
+
+func (r *ProjectRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM projects WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return expectRow(result)
+}
+
+func (r *ProjectRepository) Members(ctx context.Context, id uuid.UUID) ([]*domain.Membership, error) {
+	// The outer join keeps one row for a project without members, so a
+	// missing project can be told apart from an empty one.
+	query := `
+		SELECT p.id, m.user_id, m.role, m.created_at, m.updated_at
+		FROM projects p
+		LEFT JOIN project_members m ON m.project_id = p.id
+		WHERE p.id = $1
+		ORDER BY m.user_id COLLATE "C"
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := false
+	memberships := []*domain.Membership{}
+	for rows.Next() {
+		found = true
+
+		var projectID uuid.UUID
+		var user, role sql.NullString
+		var createdAt, updatedAt sql.NullTime
+		if err := rows.Scan(&projectID, &user, &role, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		if !user.Valid {
+			continue
+		}
+
+		memberships = append(memberships, &domain.Membership{
+			ProjectID: projectID,
+			User:      user.String,
+			Role:      domain.ProjectRole(role.String),
+			CreatedAt: createdAt.Time,
+			UpdatedAt: updatedAt.Time,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errs.ErrNotFound
+	}
+
+	return memberships, nil
+}
+
+func (r *ProjectRepository) Roles(ctx context.Context, user string) (map[uuid.UUID]domain.ProjectRole, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT project_id, role FROM project_members WHERE user_id = $1`, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make(map[uuid.UUID]domain.ProjectRole)
+	for rows.Next() {
+		var id uuid.UUID
+		var role domain.ProjectRole
+		if err := rows.Scan(&id, &role); err != nil {
+			return nil, err
+		}
+		roles[id] = role
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *ProjectRepository) SetMember(ctx context.Context, membership *domain.Membership) error {
+	// Selecting from projects turns a missing project into no row instead
+	// of a foreign key violation.
+	query := `
+		INSERT INTO project_members (project_id, user_id, role, created_at, updated_at)
+		SELECT id, $2, $3, $4, $5 FROM projects WHERE id = $1
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		membership.ProjectID,
+		membership.User,
+		membership.Role,
+		membership.CreatedAt,
+		membership.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return expectRow(result)
+}
+
+func (r *ProjectRepository) RemoveMember(ctx context.Context, id uuid.UUID, user string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`, id, user)
+	if err != nil {
+		return err
+	}
+	return expectRow(result)
+}
+
+// expectRow turns a statement that changed no rows into errs.ErrNotFound.
+func expectRow(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/rs/zerolog"
+)
+
+// RateLimitStore keeps token buckets in Postgres so that every replica
+// enforces the same limits. Each Take locks the bucket row for the duration
+// of a short transaction. Buckets are dropped by Prune once they have
+// refilled completely.
+type RateLimitStore struct {
+	db *sql.DB
+}
+
+func NewRateLimitStore(db *sql.DB) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, policy domain.RateLimitPolicy) (result domain.RateLimitResult, err error) {
+	now := time.Now().UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	initial := domain.NewTokenBucket(policy, now)
+
+	insert := `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at, idle_after)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (key) DO NOTHING
+	`
+	if _, err = tx.ExecContext(ctx, insert, key, initial.Tokens, initial.UpdatedAt); err != nil {
+		return result, err
+	}
+
+	var bucket domain.TokenBucket
+	selectQuery := `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`
+	if err = tx.QueryRowContext(ctx, selectQuery, key).Scan(&bucket.Tokens, &bucket.UpdatedAt); err != nil {
+		return result, err
+	}
+
+	result = bucket.Take(policy, now)
+
+	update := `UPDATE rate_limit_buckets SET tokens = $1, updated_at = $2, idle_after = $3 WHERE key = $4`
+	if _, err = tx.ExecContext(ctx, update, bucket.Tokens, bucket.UpdatedAt, now.Add(result.ResetAfter), key); err != nil {
+		return result, err
+	}
+
+	err = tx.Commit()
+	return result, err
+}
+
+// Prune deletes the buckets that have refilled completely; recreating them
+// later gives the same result. It returns how many were deleted.
+func (s *RateLimitStore) Prune(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE idle_after < $1`, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// KeepPruned runs Prune every interval until ctx is done.
+func (s *RateLimitStore) KeepPruned(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pruned, err := s.Prune(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to prune rate limit buckets")
+			}
+			continue
+		}
+		zerolog.Ctx(ctx).Debug().Int64("buckets", pruned).Msg("Pruned idle rate limit buckets")
+	}
+}
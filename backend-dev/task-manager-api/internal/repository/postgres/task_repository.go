@@ -3,26 +3,68 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// dbtx is what the queries need from the database, so the same methods
+// work both inside and outside a transaction. instrumentedDB provides it on
+// top of *sql.DB and *sql.Tx.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (sqlRows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqlRows is the part of *sql.Rows the queries use.
+type sqlRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+// copyThreshold is the batch size from which CreateMany switches to COPY.
+const copyThreshold = 500
+
 type TaskRepository struct {
-	db *sql.DB
+	db   dbtx
+	conn *sql.DB
+
+	// tx is set on repositories handed out by WithTx; depth counts the
+	// savepoints opened on top of it.
+	tx    *sql.Tx
+	depth int
 }
 
-func NewConnection(connectionString string) (*sql.DB, error) {
-	config, err := pgx.ParseConfig(connectionString)
+func NewConnection(cfg config.DatabaseConfig) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(cfg.URL)
 	if err != nil {
 		return nil, err
 	}
 
-	db := stdlib.OpenDB(*config)
+	db := stdlib.OpenDB(*connConfig)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	if err := db.Ping(); err != nil {
 		return nil, err
 	}
@@ -31,22 +73,94 @@ func NewConnection(connectionString string) (*sql.DB, error) {
 }
 
 func NewTaskRepository(db *sql.DB) *TaskRepository {
-	return &TaskRepository{db: db}
+	return &TaskRepository{db: instrumentedDB{db}, conn: db}
+}
+
+func (r *TaskRepository) WithTx(ctx context.Context, fn func(repo repository.TaskRepository) error) error {
+	if r.tx != nil {
+		return r.withSavepoint(ctx, fn)
+	}
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&TaskRepository{db: instrumentedDB{tx}, conn: r.conn, tx: tx}); err != nil {
+		zerolog.Ctx(ctx).Debug().Err(err).Msg("Rolling back transaction")
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *TaskRepository) withSavepoint(ctx context.Context, fn func(repo repository.TaskRepository) error) error {
+	nested := &TaskRepository{db: r.db, conn: r.conn, tx: r.tx, depth: r.depth + 1}
+	name := fmt.Sprintf("sp_%d", nested.depth)
+
+	if _, err := r.db.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(nested); err != nil {
+		if _, rbErr := r.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// unscopedLock is the advisory lock LockProject takes for tasks in no
+// project, which have no project row to lock.
+const unscopedLock = 0x7461736b73
+
+func (r *TaskRepository) LockProject(ctx context.Context, projectID *uuid.UUID) error {
+	if r.tx == nil {
+		return nil
+	}
+	if projectID == nil {
+		_, err := r.db.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", unscopedLock)
+		return err
+	}
+
+	// A project with tasks can't be deleted, so there is a row to lock.
+	var id uuid.UUID
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM projects WHERE id = $1 FOR UPDATE", *projectID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errs.ErrNotFound
+	}
+	return err
 }
 
 func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 	query := `
-		INSERT INTO tasks (id, title, description, status, due_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO tasks (id, project_id, title, description, status, priority, rank, due_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
 
 	_, err := r.db.ExecContext(
 		ctx,
 		query,
 		task.ID,
+		task.ProjectID,
 		task.Title,
 		task.Description,
 		task.Status,
+		task.Priority,
+		task.Rank,
 		task.DueDate,
 		task.CreatedAt,
 		task.UpdatedAt,
@@ -56,25 +170,9 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 }
 
 func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
-	query := `
-		SELECT id, title, description, status, due_date, created_at, updated_at
-		FROM tasks
-		WHERE id = $1
-	`
-
-	var task domain.Task
-	var dueDate sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&dueDate,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1`
 
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errs.ErrNotFound
@@ -82,63 +180,244 @@ func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Tas
 		return nil, err
 	}
 
-	if dueDate.Valid {
-		task.DueDate = &dueDate.Time
+	return task, nil
+}
+
+func (r *TaskRepository) List(ctx context.Context) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+
+	err := r.ForEach(ctx, func(task *domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &task, nil
+	return tasks, nil
 }
 
-func (r *TaskRepository) List(ctx context.Context) ([]*domain.Task, error) {
+func (r *TaskRepository) ForEach(ctx context.Context, fn func(task *domain.Task) error) error {
+	return r.Find(ctx, domain.TaskFilter{}, fn)
+}
+
+func (r *TaskRepository) Find(ctx context.Context, filter domain.TaskFilter, fn func(task *domain.Task) error) error {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		args = append(args, statuses)
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+
+	if len(filter.IDs) > 0 {
+		ids := make([]string, len(filter.IDs))
+		for i, id := range filter.IDs {
+			ids[i] = id.String()
+		}
+		args = append(args, ids)
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d::uuid[])", len(args)))
+	}
+
+	if filter.HasDueDate {
+		conditions = append(conditions, "due_date IS NOT NULL")
+	}
+
+	if filter.ProjectID != nil {
+		args = append(args, *filter.ProjectID)
+		conditions = append(conditions, fmt.Sprintf("project_id = $%d", len(args)))
+	}
+
+	if filter.NoProject {
+		conditions = append(conditions, "project_id IS NULL")
+	}
+
+	if filter.RestrictProjects {
+		ids := make([]string, len(filter.Projects))
+		for i, id := range filter.Projects {
+			ids[i] = id.String()
+		}
+		args = append(args, ids)
+		conditions = append(conditions, fmt.Sprintf("project_id = ANY($%d::uuid[])", len(args)))
+	}
+
+	if filter.Assignee != "" {
+		args = append(args, filter.Assignee)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM task_people p WHERE p.task_id = tasks.id AND p.relation = 'assignee' AND p.user_id = $%d)", len(args)))
+	}
+
+	if filter.Unassigned {
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM task_people p WHERE p.task_id = tasks.id AND p.relation = 'assignee')")
+	}
+
+	if filter.Search != "" {
+		args = append(args, "%"+escapeLike(filter.Search)+"%")
+		conditions = append(conditions, fmt.Sprintf("(title ILIKE $%d OR description ILIKE $%d)", len(args), len(args)))
+	}
+
+	if filter.After != nil && filter.RankOrder {
+		args = append(args, filter.After.Rank, filter.After.CreatedAt, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf(`(rank COLLATE "C", created_at, id) > ($%d, $%d, $%d)`, len(args)-2, len(args)-1, len(args)))
+	} else if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if filter.RankOrder {
+		query += " ORDER BY " + rankOrder
+	} else {
+		query += " ORDER BY created_at DESC, id DESC"
+	}
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// CreateMany inserts tasks in bulk. Large batches outside a transaction are
+// loaded with COPY; everything else falls back to one INSERT per task inside
+// a transaction (or savepoint).
+func (r *TaskRepository) CreateMany(ctx context.Context, tasks []*domain.Task) error {
+	if r.tx != nil || len(tasks) < copyThreshold {
+		return r.WithTx(ctx, func(repo repository.TaskRepository) error {
+			for _, task := range tasks {
+				if err := repo.Create(ctx, task); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	conn, err := r.conn.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	zerolog.Ctx(ctx).Debug().Int("rows", len(tasks)).Msg("Copying tasks")
+
+	ctx, span := tracer.Start(ctx, "COPY tasks",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			attribute.String("db.statement", "COPY tasks FROM STDIN"),
+			attribute.Int("db.rows", len(tasks)),
+		),
+	)
+	defer span.End()
+
+	err = conn.Raw(func(driverConn any) error {
+		pgxConn := driverConn.(*stdlib.Conn).Conn()
+
+		_, err := pgxConn.CopyFrom(
+			ctx,
+			pgx.Identifier{"tasks"},
+			[]string{"id", "project_id", "title", "description", "status", "priority", "rank", "due_date", "created_at", "updated_at"},
+			pgx.CopyFromSlice(len(tasks), func(i int) ([]any, error) {
+				task := tasks[i]
+				return []any{
+					task.ID,
+					task.ProjectID,
+					task.Title,
+					task.Description,
+					string(task.Status),
+					string(task.Priority),
+					task.Rank,
+					task.DueDate,
+					task.CreatedAt,
+					task.UpdatedAt,
+				}, nil
+			}),
+		)
+
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (r *TaskRepository) Stats(ctx context.Context, now time.Time) (*domain.TaskStats, error) {
 	query := `
-		SELECT id, title, description, status, due_date, created_at, updated_at
+		SELECT status, COUNT(*), COUNT(*) FILTER (WHERE due_date < $1)
 		FROM tasks
-		ORDER BY created_at DESC
+		GROUP BY status
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, now)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var tasks []*domain.Task
+	stats := &domain.TaskStats{ByStatus: map[domain.TaskStatus]int{}}
 
 	for rows.Next() {
-		var task domain.Task
-		var dueDate sql.NullTime
+		var status domain.TaskStatus
+		var count, overdue int
 
-		if err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&dueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		); err != nil {
+		if err := rows.Scan(&status, &count, &overdue); err != nil {
 			return nil, err
 		}
 
-		if dueDate.Valid {
-			task.DueDate = &dueDate.Time
+		stats.ByStatus[status] = count
+		if status != domain.TaskStatusDone {
+			stats.Overdue += overdue
 		}
-
-		tasks = append(tasks, &task)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return tasks, nil
+	return stats, nil
 }
 
 func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 	query := `
 		UPDATE tasks
-		SET title = $1, description = $2, status = $3, due_date = $4, updated_at = $5
-		WHERE id = $6
+		SET title = $1, description = $2, status = $3, priority = $4, rank = $5, due_date = $6, updated_at = $7
+		WHERE id = $8
 	`
 
 	result, err := r.db.ExecContext(
@@ -147,6 +426,8 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 		task.Title,
 		task.Description,
 		task.Status,
+		task.Priority,
+		task.Rank,
 		task.DueDate,
 		task.UpdatedAt,
 		task.ID,
@@ -187,3 +468,271 @@ func (r *TaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+func (r *TaskRepository) AddUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	query := `
+		INSERT INTO task_people (task_id, relation, user_id, created_at)
+		SELECT id, $2, $3, $4 FROM tasks WHERE id = $1
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, relation, user, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return r.userChanged(ctx, id, result)
+}
+
+func (r *TaskRepository) RemoveUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (bool, error) {
+	query := `DELETE FROM task_people WHERE task_id = $1 AND relation = $2 AND user_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, id, relation, user)
+	if err != nil {
+		return false, err
+	}
+	return r.userChanged(ctx, id, result)
+}
+
+func (r *TaskRepository) AddHistory(ctx context.Context, entry *domain.TaskHistoryEntry) error {
+	query := `
+		INSERT INTO task_history (id, task_id, change, relation, user_id, actor, created_at)
+		SELECT $1, id, $3, $4, $5, $6, $7 FROM tasks WHERE id = $2
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		entry.ID,
+		entry.TaskID,
+		entry.Change,
+		entry.Relation,
+		entry.User,
+		entry.Actor,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TaskRepository) History(ctx context.Context, id uuid.UUID) ([]*domain.TaskHistoryEntry, error) {
+	query := `
+		SELECT id, task_id, change, relation, user_id, actor, created_at
+		FROM task_history
+		WHERE task_id = $1
+		ORDER BY seq
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.TaskHistoryEntry{}
+	for rows.Next() {
+		var entry domain.TaskHistoryEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TaskID,
+			&entry.Change,
+			&entry.Relation,
+			&entry.User,
+			&entry.Actor,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// A task without history has no rows either.
+	if len(entries) == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1)`, id).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errs.ErrNotFound
+		}
+	}
+
+	return entries, nil
+}
+
+// userChanged reports whether AddUser or RemoveUser changed a row, telling
+// a missing task apart from a user who already was or wasn't there.
+func (r *TaskRepository) userChanged(ctx context.Context, id uuid.UUID, result sql.Result) (bool, error) {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected > 0 {
+		return true, nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, errs.ErrNotFound
+	}
+	return false, nil
+}
+
+// taskColumns selects what scanTask reads, the task's people as JSON
+// arrays sorted the way the memory repository sorts them.
+const taskColumns = `id, project_id, title, description, status, priority, rank, due_date, created_at, updated_at,
+	COALESCE((SELECT json_agg(user_id ORDER BY user_id COLLATE "C") FROM task_people WHERE task_id = tasks.id AND relation = 'assignee'), '[]'),
+	COALESCE((SELECT json_agg(user_id ORDER BY user_id COLLATE "C") FROM task_people WHERE task_id = tasks.id AND relation = 'watcher'), '[]')`
+
+// rankOrder sorts by the rank order of repository.TaskRepository, using the
+// byte order pkg/rank relies on whatever the database collation is.
+const rankOrder = `rank COLLATE "C", created_at, id`
+
+func (r *TaskRepository) LastRank(ctx context.Context) (string, error) {
+	query := `SELECT COALESCE(MAX(rank COLLATE "C"), '') FROM tasks`
+
+	var last string
+	err := r.db.QueryRowContext(ctx, query).Scan(&last)
+	return last, err
+}
+
+func (r *TaskRepository) NeighborRank(ctx context.Context, task *domain.Task, before bool, skip uuid.UUID) (string, error) {
+	cmp, dir := ">", "ASC"
+	if before {
+		cmp, dir = "<", "DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT rank
+		FROM tasks
+		WHERE (rank COLLATE "C", created_at, id) %s ($1, $2, $3) AND id <> $4
+		ORDER BY rank COLLATE "C" %s, created_at %s, id %s
+		LIMIT 1
+	`, cmp, dir, dir, dir)
+	if r.tx != nil {
+		query += " FOR UPDATE"
+	}
+
+	var neighbor string
+	err := r.db.QueryRowContext(ctx, query, task.Rank, task.CreatedAt, task.ID, skip).Scan(&neighbor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errs.ErrNotFound
+	}
+	return neighbor, err
+}
+
+func (r *TaskRepository) RankStats(ctx context.Context) (*domain.RankStats, error) {
+	query := `SELECT COALESCE(MAX(LENGTH(rank)), 0), COUNT(*) FILTER (WHERE rank = '') FROM tasks`
+
+	var stats domain.RankStats
+	if err := r.db.QueryRowContext(ctx, query).Scan(&stats.MaxLength, &stats.Unranked); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *TaskRepository) Rerank(ctx context.Context, ranks func(n int) ([]string, error)) error {
+	if r.tx == nil {
+		return r.WithTx(ctx, func(repo repository.TaskRepository) error {
+			return repo.Rerank(ctx, ranks)
+		})
+	}
+
+	// Readers carry on, but writers wait until the new ranks are committed,
+	// so nothing moves between reading the order and rewriting it.
+	if _, err := r.db.ExecContext(ctx, "LOCK TABLE tasks IN EXCLUSIVE MODE"); err != nil {
+		return err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM tasks ORDER BY `+rankOrder)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	keys, err := ranks(len(ids))
+	if err != nil {
+		return err
+	}
+	if len(keys) != len(ids) {
+		return fmt.Errorf("got %d ranks for %d tasks", len(keys), len(ids))
+	}
+
+	query := `
+		UPDATE tasks
+		SET rank = ranked.rank
+		FROM unnest($1::uuid[], $2::text[]) AS ranked(id, rank)
+		WHERE tasks.id = ranked.id
+	`
+
+	_, err = r.db.ExecContext(ctx, query, ids, keys)
+	return err
+}
+
+// scanTask reads a row of taskColumns from *sql.Row or *sql.Rows.
+func scanTask(row interface{ Scan(dest ...any) error }) (*domain.Task, error) {
+	var task domain.Task
+	var projectID uuid.NullUUID
+	var dueDate sql.NullTime
+	var assignees, watchers []byte
+
+	if err := row.Scan(
+		&task.ID,
+		&projectID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&task.Rank,
+		&dueDate,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&assignees,
+		&watchers,
+	); err != nil {
+		return nil, err
+	}
+
+	if projectID.Valid {
+		task.ProjectID = &projectID.UUID
+	}
+	if dueDate.Valid {
+		task.DueDate = &dueDate.Time
+	}
+
+	if err := json.Unmarshal(assignees, &task.Assignees); err != nil {
+		return nil, fmt.Errorf("decoding assignees of task %s: %w", task.ID, err)
+	}
+	if err := json.Unmarshal(watchers, &task.Watchers); err != nil {
+		return nil, fmt.Errorf("decoding watchers of task %s: %w", task.ID, err)
+	}
+
+	return &task, nil
+}
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+)
+
+const viewColumns = `id, name, owner, team, definition, schema_version, problem, created_at, updated_at`
+
+type ViewRepository struct {
+	db dbtx
+}
+
+func NewViewRepository(db *sql.DB) *ViewRepository {
+	return &ViewRepository{db: instrumentedDB{db}}
+}
+
+func (r *ViewRepository) Create(ctx context.Context, view *domain.View) error {
+	definition, err := json.Marshal(view.ViewDefinition)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO saved_views (id, name, owner, team, definition, schema_version, problem, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		view.ID,
+		view.Name,
+		view.Owner,
+		view.Team,
+		definition,
+		view.SchemaVersion,
+		view.Problem,
+		view.CreatedAt,
+		view.UpdatedAt,
+	)
+	return err
+}
+
+func (r *ViewRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.View, error) {
+	query := `SELECT ` + viewColumns + ` FROM saved_views WHERE id = $1`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	views, err := scanViews(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(views) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	return views[0], nil
+}
+
+func (r *ViewRepository) List(ctx context.Context, owner, team string) ([]*domain.View, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	if owner != "" {
+		args = append(args, owner)
+		conditions = append(conditions, fmt.Sprintf("owner = $%d", len(args)))
+	}
+
+	if team != "" {
+		args = append(args, team)
+		conditions = append(conditions, fmt.Sprintf("team = $%d", len(args)))
+	}
+
+	query := `SELECT ` + viewColumns + ` FROM saved_views`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " OR ")
+	}
+	query += " ORDER BY name, id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanViews(rows)
+}
+
+func (r *ViewRepository) ListOutdated(ctx context.Context, version int) ([]*domain.View, error) {
+	query := `SELECT ` + viewColumns + ` FROM saved_views WHERE schema_version < $1`
+
+	rows, err := r.db.QueryContext(ctx, query, version)
+	if err != nil {
+		return nil, err
+	}
+	return scanViews(rows)
+}
+
+func (r *ViewRepository) Update(ctx context.Context, view *domain.View) error {
+	definition, err := json.Marshal(view.ViewDefinition)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE saved_views
+		SET name = $1, team = $2, definition = $3, schema_version = $4, problem = $5, updated_at = $6
+		WHERE id = $7
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		view.Name,
+		view.Team,
+		definition,
+		view.SchemaVersion,
+		view.Problem,
+		view.UpdatedAt,
+		view.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ViewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM saved_views WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanViews(rows sqlRows) ([]*domain.View, error) {
+	defer rows.Close()
+
+	var views []*domain.View
+	for rows.Next() {
+		var view domain.View
+		var definition []byte
+
+		if err := rows.Scan(
+			&view.ID,
+			&view.Name,
+			&view.Owner,
+			&view.Team,
+			&definition,
+			&view.SchemaVersion,
+			&view.Problem,
+			&view.CreatedAt,
+			&view.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		// Fields the definition no longer has are dropped here; the
+		// startup revalidation reports what the rest refers to.
+		if err := json.Unmarshal(definition, &view.ViewDefinition); err != nil {
+			return nil, fmt.Errorf("decoding definition of view %s: %w", view.ID, err)
+		}
+
+		views = append(views, &view)
+	}
+
+	return views, rows.Err()
+}
@@ -0,0 +1,246 @@
+// Package server assembles the REST API: the services on top of the
+// repositories, and the gin router with its middleware and routes. The
+// binary and the tests build the API the same way through New.
+package server
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/api/openapi"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/auth"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/graph"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/handlers"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/health"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/metrics"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/middlewares"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/notify"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository/memory"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Repositories are the stores the API keeps its data in.
+type Repositories struct {
+	Tasks         repository.TaskRepository
+	CalendarFeeds repository.CalendarFeedRepository
+	Views         repository.ViewRepository
+	Boards        repository.BoardRepository
+	Projects      repository.ProjectRepository
+	APITokens     repository.APITokenRepository
+	Idempotency   repository.IdempotencyRepository
+	RateLimits    repository.RateLimitStore
+}
+
+// MemoryRepositories returns repositories that keep everything in process
+// memory.
+func MemoryRepositories() Repositories {
+	return Repositories{
+		Tasks:         memory.NewTaskRepository(),
+		CalendarFeeds: memory.NewCalendarFeedRepository(),
+		Views:         memory.NewViewRepository(),
+		Boards:        memory.NewBoardRepository(),
+		Projects:      memory.NewProjectRepository(),
+		APITokens:     memory.NewAPITokenRepository(),
+		Idempotency:   memory.NewIdempotencyRepository(),
+		RateLimits:    memory.NewRateLimitStore(),
+	}
+}
+
+// Options carries what New shares with the rest of the process. Zero
+// fields get a private default.
+type Options struct {
+	Log zerolog.Logger
+	// RateLimit returns the rate limits in effect; nil means those of the
+	// configuration given to New.
+	RateLimit func() config.RateLimitConfig
+	Metrics   *metrics.Metrics
+	Health    *health.Registry
+	// Notifier delivers notifications to users; nil means notify.Log.
+	Notifier notify.Notifier
+}
+
+// Server is the assembled API.
+type Server struct {
+	Router        *gin.Engine
+	Authenticator *auth.Authenticator
+	Tasks         *service.TaskService
+	Views         *service.ViewService
+}
+
+// New builds the services and the router. It fails when the OpenAPI
+// document or the access policy doesn't match the routes.
+func New(cfg *config.Config, repos Repositories, opts Options) (*Server, error) {
+	log := opts.Log
+	if opts.RateLimit == nil {
+		opts.RateLimit = func() config.RateLimitConfig { return cfg.RateLimit }
+	}
+	if opts.Metrics == nil {
+		opts.Metrics = metrics.New(nil)
+	}
+	if opts.Health == nil {
+		opts.Health = health.NewRegistry(cfg.Health.CacheTTL)
+	}
+	if opts.Notifier == nil {
+		opts.Notifier = notify.Log{}
+	}
+
+	// Identify callers and check their project roles and token scopes
+	authenticator := auth.New(cfg.Auth.JWTSecret, repos.APITokens)
+	accessPolicy := policy.New(repos.Projects, cfg.Auth.EnforcePolicy)
+
+	// Initialize services
+	taskService := service.NewTaskService(repos.Tasks, repos.Boards, repos.Projects, accessPolicy, opts.Notifier)
+	calendarService := service.NewCalendarService(repos.CalendarFeeds, repos.Tasks, accessPolicy)
+	viewService := service.NewViewService(repos.Views, repos.Tasks, accessPolicy)
+	boardService := service.NewBoardService(repos.Boards, repos.Tasks, repos.Projects, accessPolicy)
+	projectService := service.NewProjectService(repos.Projects, repos.Tasks, repos.Boards, accessPolicy)
+	apiTokenService := service.NewAPITokenService(repos.APITokens)
+
+	// Initialize handlers
+	taskHandler := handler.NewTaskHandler(taskService)
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+	viewHandler := handler.NewViewHandler(viewService)
+	boardHandler := handler.NewBoardHandler(boardService)
+	projectHandler := handler.NewProjectHandler(projectService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	healthHandler := handler.NewHealthHandler(opts.Health)
+
+	// Load the OpenAPI document
+	spec, err := openapi.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading OpenAPI document: %w", err)
+	}
+	docsHandler := handler.NewDocsHandler(spec.JSON)
+	openAPIValidator, err := middleware.OpenAPIValidator(spec.Doc, cfg.OpenAPI, log)
+	if err != nil {
+		return nil, fmt.Errorf("building OpenAPI validator: %w", err)
+	}
+
+	// Initialize router
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName))
+	router.Use(middleware.RequestID(log))
+	router.Use(middleware.Logger(log))
+	router.Use(middleware.Metrics(opts.Metrics))
+
+	// Health checks, registered before rate limiting so probes are never
+	// throttled
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/health", healthHandler.Readyz)
+
+	// Authenticate first so rate limits can be kept per user
+	router.Use(middleware.Authenticate(authenticator, log))
+	router.Use(middleware.RateLimit(opts.RateLimit, repos.RateLimits, log))
+	router.Use(openAPIValidator)
+	router.Use(middleware.RequireUser(accessPolicy))
+	router.Use(middleware.RequireScope())
+
+	// Register routes
+	v1 := router.Group("/api/v1", middleware.Idempotency(repos.Idempotency, int64(cfg.Idempotency.MaxBodyMB)<<20, log))
+	{
+		tasks := v1.Group("/tasks")
+		{
+			tasks.GET("", taskHandler.ListTasks)
+			tasks.POST("", taskHandler.CreateTask)
+			tasks.GET("/export", taskHandler.ExportTasks)
+			tasks.POST("/import", taskHandler.ImportTasks)
+			tasks.GET("/:id", taskHandler.GetTask)
+			tasks.PUT("/:id", taskHandler.UpdateTask)
+			tasks.PATCH("/:id", taskHandler.PatchTask)
+			tasks.DELETE("/:id", taskHandler.DeleteTask)
+			tasks.POST("/:id/move", taskHandler.MoveTask)
+			tasks.POST("/:id/assignees", taskHandler.AssignTask)
+			tasks.DELETE("/:id/assignees/:user", taskHandler.UnassignTask)
+			tasks.POST("/:id/watchers", taskHandler.WatchTask)
+			tasks.DELETE("/:id/watchers/:user", taskHandler.UnwatchTask)
+			tasks.GET("/:id/history", taskHandler.TaskHistory)
+		}
+
+		v1.POST("/tasks:action", taskHandler.BatchTasks)
+
+		projects := v1.Group("/projects")
+		{
+			projects.GET("", projectHandler.ListProjects)
+			projects.POST("", projectHandler.CreateProject)
+			projects.GET("/:id", projectHandler.GetProject)
+			projects.PUT("/:id", projectHandler.UpdateProject)
+			projects.DELETE("/:id", projectHandler.DeleteProject)
+			projects.GET("/:id/members", projectHandler.ListMembers)
+			projects.PUT("/:id/members/:user", projectHandler.SetMember)
+			projects.DELETE("/:id/members/:user", projectHandler.RemoveMember)
+		}
+
+		tokens := v1.Group("/tokens")
+		{
+			tokens.GET("", apiTokenHandler.ListTokens)
+			tokens.POST("", apiTokenHandler.CreateToken)
+			tokens.GET("/:id", apiTokenHandler.GetToken)
+			tokens.DELETE("/:id", apiTokenHandler.RevokeToken)
+		}
+
+		feeds := v1.Group("/calendar-feeds")
+		{
+			feeds.POST("", calendarHandler.CreateFeed)
+			feeds.DELETE("/:id", calendarHandler.RevokeFeed)
+		}
+
+		views := v1.Group("/views")
+		{
+			views.GET("", viewHandler.ListViews)
+			views.POST("", viewHandler.CreateView)
+			views.GET("/:id", viewHandler.GetView)
+			views.PUT("/:id", viewHandler.UpdateView)
+			views.DELETE("/:id", viewHandler.DeleteView)
+			views.GET("/:id/tasks", viewHandler.ViewTasks)
+		}
+
+		boards := v1.Group("/boards")
+		{
+			boards.GET("", boardHandler.ListBoards)
+			boards.POST("", boardHandler.CreateBoard)
+			boards.GET("/:id", boardHandler.GetBoard)
+			boards.PUT("/:id", boardHandler.UpdateBoard)
+			boards.DELETE("/:id", boardHandler.DeleteBoard)
+			boards.GET("/:id/versions", boardHandler.BoardVersions)
+		}
+	}
+
+	// iCalendar feed, authorized by the secret token in the URL
+	router.GET("/calendar/:token", calendarHandler.Feed)
+
+	// GraphQL; subscriptions upgrade GET /graphql to a WebSocket
+	graphHandler := gin.WrapH(graph.NewHandler(taskService, cfg.GraphQL))
+	router.GET("/graphql", graphHandler)
+	router.POST("/graphql", graphHandler)
+	if cfg.GraphQL.Playground {
+		router.GET("/graphql/playground", gin.WrapH(graph.PlaygroundHandler("/graphql")))
+	}
+
+	// API documentation
+	router.GET("/openapi.json", docsHandler.Spec)
+	router.GET("/docs", docsHandler.Page)
+
+	// Refuse routes the document doesn't describe, or the other way round
+	if err := spec.CheckRoutes(router.Routes()); err != nil {
+		return nil, fmt.Errorf("OpenAPI document is out of date: %w", err)
+	}
+
+	// Likewise for routes without an access rule
+	if err := policy.CheckRoutes(router.Routes()); err != nil {
+		return nil, fmt.Errorf("access policy is out of date: %w", err)
+	}
+
+	return &Server{
+		Router:        router,
+		Authenticator: authenticator,
+		Tasks:         taskService,
+		Views:         viewService,
+	}, nil
+}
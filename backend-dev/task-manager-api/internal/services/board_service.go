@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+type BoardService struct {
+	boards   repository.BoardRepository
+	tasks    repository.TaskRepository
+	projects repository.ProjectRepository
+	policy   *policy.Policy
+}
+
+// NewBoardService returns a service for the boards in boards, showing the
+// tasks in tasks. Reading a board needs tasks:read and changing it
+// boards:manage in the board's project, as policy decides.
+func NewBoardService(boards repository.BoardRepository, tasks repository.TaskRepository, projects repository.ProjectRepository, policy *policy.Policy) *BoardService {
+	return &BoardService{boards: boards, tasks: tasks, projects: projects, policy: policy}
+}
+
+func (s *BoardService) CreateBoard(ctx context.Context, input domain.CreateBoardInput) (board *domain.Board, err error) {
+	ctx, span := tracer.Start(ctx, "BoardService.CreateBoard")
+	defer func() { endSpan(span, err) }()
+
+	input.Name = strings.TrimSpace(input.Name)
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeCreate(ctx, input); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	board = &domain.Board{
+		ID:              uuid.New(),
+		ProjectID:       input.ProjectID,
+		BoardDefinition: input.BoardDefinition,
+		Version:         1,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.boards.Create(ctx, board); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("board_id", board.ID).Msg("Board created")
+	return board, nil
+}
+
+func (s *BoardService) GetBoard(ctx context.Context, id uuid.UUID) (board *domain.Board, err error) {
+	ctx, span := tracer.Start(ctx, "BoardService.GetBoard")
+	defer func() { endSpan(span, err) }()
+
+	return s.getBoard(ctx, id, policy.TasksRead)
+}
+
+// ListBoards returns the boards of the projects whose tasks the caller may
+// read. Boards in no project are only listed when the policy isn't
+// enforced.
+func (s *BoardService) ListBoards(ctx context.Context) (boards []*domain.Board, err error) {
+	ctx, span := tracer.Start(ctx, "BoardService.ListBoards")
+	defer func() { endSpan(span, err) }()
+
+	projects, restricted, err := s.policy.Permitted(ctx, policy.TasksRead)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.boards.List(ctx)
+	if err != nil || !restricted {
+		return all, err
+	}
+
+	boards = make([]*domain.Board, 0, len(all))
+	for _, board := range all {
+		if board.ProjectID != nil && slices.Contains(projects, *board.ProjectID) {
+			boards = append(boards, board)
+		}
+	}
+	return boards, nil
+}
+
+// UpdateBoard applies input to the board as its next version. It fails with
+// errs.ErrConflict when input was made against an older version.
+func (s *BoardService) UpdateBoard(ctx context.Context, id uuid.UUID, input domain.UpdateBoardInput) (board *domain.Board, err error) {
+	ctx, span := tracer.Start(ctx, "BoardService.UpdateBoard")
+	defer func() { endSpan(span, err) }()
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	board, err = s.getBoard(ctx, id, policy.BoardsManage)
+	if err != nil {
+		return nil, err
+	}
+	if board.Version != input.Version {
+		return nil, fmt.Errorf("%w: board is at version %d, not %d", errs.ErrConflict, board.Version, input.Version)
+	}
+
+	if input.Name != nil {
+		board.Name = strings.TrimSpace(*input.Name)
+	}
+
+	if input.Columns != nil {
+		board.Columns = *input.Columns
+	}
+
+	if err := board.Validate(); err != nil {
+		return nil, err
+	}
+	board.Version++
+	board.UpdatedAt = time.Now()
+
+	if err := s.boards.Update(ctx, board); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("board_id", id).Int("version", board.Version).Msg("Board updated")
+	return board, nil
+}
+
+func (s *BoardService) DeleteBoard(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracer.Start(ctx, "BoardService.DeleteBoard")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.getBoard(ctx, id, policy.BoardsManage); err != nil {
+		return err
+	}
+	if err := s.boards.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("board_id", id).Msg("Board deleted")
+	return nil
+}
+
+// BoardVersions returns every version of the board's definition, oldest
+// first.
+func (s *BoardService) BoardVersions(ctx context.Context, id uuid.UUID) (versions []*domain.BoardVersion, err error) {
+	ctx, span := tracer.Start(ctx, "BoardService.BoardVersions")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.getBoard(ctx, id, policy.TasksRead); err != nil {
+		return nil, err
+	}
+	return s.boards.Versions(ctx, id)
+}
+
+// BoardTasks returns the board and the first limit tasks of each column, in
+// rank order. With column set only that column is returned, starting after
+// the cursor when one is given. Columns list and count the tasks of the
+// board's project, or those in no project, that the caller may read.
+func (s *BoardService) BoardTasks(ctx context.Context, id uuid.UUID, column string, after *domain.TaskCursor, limit int) (board *domain.Board, pages []*domain.BoardColumnPage, err error) {
+	ctx, span := tracer.Start(ctx, "BoardService.BoardTasks")
+	defer func() { endSpan(span, err) }()
+
+	board, err = s.getBoard(ctx, id, policy.TasksRead)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := board.Columns
+	if column != "" {
+		c := board.Column(column)
+		if c == nil {
+			return nil, nil, fmt.Errorf("%w: board has no column %q", errs.ErrInvalidInput, column)
+		}
+		columns = []domain.BoardColumn{*c}
+	}
+
+	for _, c := range columns {
+		page := &domain.BoardColumnPage{BoardColumn: c, Tasks: []*domain.Task{}}
+
+		filter := board.TaskFilter(c)
+		if err := s.policy.Restrict(ctx, &filter); err != nil {
+			return nil, nil, err
+		}
+		if page.Count, err = countTasks(ctx, s.tasks, filter); err != nil {
+			return nil, nil, err
+		}
+
+		filter.RankOrder = true
+		filter.After = after
+		// One extra row tells whether there is a next page.
+		filter.Limit = limit + 1
+		err := s.tasks.Find(ctx, filter, func(task *domain.Task) error {
+			page.Tasks = append(page.Tasks, task)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(page.Tasks) > limit {
+			page.Tasks = page.Tasks[:limit]
+			page.NextCursor = page.Tasks[limit-1].Cursor().String()
+		}
+		pages = append(pages, page)
+	}
+
+	return board, pages, nil
+}
+
+// getBoard returns the board after checking that the caller holds
+// permission in its project.
+func (s *BoardService) getBoard(ctx context.Context, id uuid.UUID, permission policy.Permission) (*domain.Board, error) {
+	board, err := s.boards.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.Authorize(ctx, board.ProjectID, permission); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// authorizeCreate checks that the caller may add boards to the project
+// input puts the board in, and that the project exists.
+func (s *BoardService) authorizeCreate(ctx context.Context, input domain.CreateBoardInput) error {
+	if err := s.policy.Authorize(ctx, input.ProjectID, policy.BoardsManage); err != nil {
+		return err
+	}
+	if input.ProjectID == nil {
+		return nil
+	}
+
+	_, err := s.projects.GetByID(ctx, *input.ProjectID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return fmt.Errorf("%w: project %s not found", errs.ErrInvalidInput, *input.ProjectID)
+	}
+	return err
+}
+
+// countTasks returns how many tasks in tasks match filter.
+func countTasks(ctx context.Context, tasks repository.TaskRepository, filter domain.TaskFilter) (int, error) {
+	n := 0
+	err := tasks.Find(ctx, filter, func(*domain.Task) error {
+		n++
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+type CalendarService struct {
+	feeds  repository.CalendarFeedRepository
+	tasks  repository.TaskRepository
+	policy *policy.Policy
+}
+
+// NewCalendarService returns a service for the feeds in feeds. A feed lists
+// the tasks in tasks that policy lets its owner read.
+func NewCalendarService(feeds repository.CalendarFeedRepository, tasks repository.TaskRepository, policy *policy.Policy) *CalendarService {
+	return &CalendarService{feeds: feeds, tasks: tasks, policy: policy}
+}
+
+// CreateFeed registers a new calendar feed and returns it together with its
+// secret token. The token is not stored and cannot be recovered later. While
+// the policy is enforced users can only create feeds for themselves.
+func (s *CalendarService) CreateFeed(ctx context.Context, input domain.CreateCalendarFeedInput) (_ *domain.CalendarFeed, _ string, err error) {
+	ctx, span := tracer.Start(ctx, "CalendarService.CreateFeed")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.SignedIn(ctx); err != nil {
+		return nil, "", err
+	}
+	if s.policy.Enforced() && input.Owner != policy.User(ctx) {
+		return nil, "", fmt.Errorf("%w: feeds can only be created for yourself", errs.ErrForbidden)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	feed := &domain.CalendarFeed{
+		ID:        uuid.New(),
+		Owner:     input.Owner,
+		TokenHash: hashCalendarToken(token),
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.feeds.Create(ctx, feed); err != nil {
+		return nil, "", err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("feed_id", feed.ID).Str("owner", feed.Owner).Msg("Calendar feed created")
+
+	return feed, token, nil
+}
+
+// RevokeFeed deletes the feed, so its token stops working. While the policy
+// is enforced only the feed's owner can revoke it.
+func (s *CalendarService) RevokeFeed(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracer.Start(ctx, "CalendarService.RevokeFeed")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.SignedIn(ctx); err != nil {
+		return err
+	}
+
+	feed, err := s.feeds.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.policy.Enforced() && feed.Owner != policy.User(ctx) {
+		return fmt.Errorf("%w: feeds can only be revoked by their owner", errs.ErrForbidden)
+	}
+
+	if err := s.feeds.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("feed_id", id).Str("owner", feed.Owner).Msg("Calendar feed revoked")
+	return nil
+}
+
+// FeedTasks resolves token to a feed and returns the tasks with a due date
+// that the feed's owner may read, optionally limited to statuses. Unknown
+// tokens yield errs.ErrNotFound.
+func (s *CalendarService) FeedTasks(ctx context.Context, token string, statuses []domain.TaskStatus) (_ []*domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "CalendarService.FeedTasks")
+	defer func() { endSpan(span, err) }()
+
+	feed, err := s.feeds.GetByTokenHash(ctx, hashCalendarToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	// The token stands in for the owner, who isn't signed in.
+	filter := domain.TaskFilter{Statuses: statuses, HasDueDate: true}
+	if err := s.policy.Restrict(policy.WithUser(ctx, feed.Owner), &filter); err != nil {
+		return nil, err
+	}
+
+	var tasks []*domain.Task
+	err = s.tasks.Find(ctx, filter, func(task *domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
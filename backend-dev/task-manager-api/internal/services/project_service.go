@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+type ProjectService struct {
+	projects repository.ProjectRepository
+	tasks    repository.TaskRepository
+	boards   repository.BoardRepository
+	policy   *policy.Policy
+}
+
+// NewProjectService returns a service for the projects in projects, and
+// their members, checking every operation with policy.
+func NewProjectService(projects repository.ProjectRepository, tasks repository.TaskRepository, boards repository.BoardRepository, policy *policy.Policy) *ProjectService {
+	return &ProjectService{projects: projects, tasks: tasks, boards: boards, policy: policy}
+}
+
+// CreateProject creates a project owned by the caller. Any signed-in user
+// can create one; without a user the project starts out with no members.
+func (s *ProjectService) CreateProject(ctx context.Context, input domain.CreateProjectInput) (project *domain.Project, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.CreateProject")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.SignedIn(ctx); err != nil {
+		return nil, err
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	project = &domain.Project{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(input.Name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	var owner *domain.Membership
+	if user := policy.User(ctx); user != "" {
+		owner = &domain.Membership{
+			ProjectID: project.ID,
+			User:      user,
+			Role:      domain.ProjectRoleOwner,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+
+	if err := s.projects.Create(ctx, project, owner); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("project_id", project.ID).Msg("Project created")
+	return project, nil
+}
+
+func (s *ProjectService) GetProject(ctx context.Context, id uuid.UUID) (project *domain.Project, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.GetProject")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.Authorize(ctx, &id, policy.ProjectsRead); err != nil {
+		return nil, err
+	}
+
+	return s.projects.GetByID(ctx, id)
+}
+
+// ListProjects returns the projects the caller is a member of, or every
+// project while the policy isn't enforced.
+func (s *ProjectService) ListProjects(ctx context.Context) (projects []*domain.Project, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.ListProjects")
+	defer func() { endSpan(span, err) }()
+
+	ids, restricted, err := s.policy.Permitted(ctx, policy.ProjectsRead)
+	if err != nil {
+		return nil, err
+	}
+	if !restricted {
+		ids = nil
+	}
+
+	return s.projects.List(ctx, ids)
+}
+
+func (s *ProjectService) UpdateProject(ctx context.Context, id uuid.UUID, input domain.UpdateProjectInput) (project *domain.Project, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.UpdateProject")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.Authorize(ctx, &id, policy.ProjectsUpdate); err != nil {
+		return nil, err
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	project, err = s.projects.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	project.Name = strings.TrimSpace(input.Name)
+	project.UpdatedAt = time.Now()
+
+	if err := s.projects.Update(ctx, project); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("project_id", id).Msg("Project updated")
+	return project, nil
+}
+
+// DeleteProject deletes the project and its memberships. A project that
+// still has tasks or boards fails with errs.ErrConflict; delete them first.
+func (s *ProjectService) DeleteProject(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.DeleteProject")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.Authorize(ctx, &id, policy.ProjectsDelete); err != nil {
+		return err
+	}
+
+	hasTasks := false
+	err = s.tasks.Find(ctx, domain.TaskFilter{ProjectID: &id, Limit: 1}, func(*domain.Task) error {
+		hasTasks = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if hasTasks {
+		return fmt.Errorf("%w: project still has tasks", errs.ErrConflict)
+	}
+
+	boards, err := s.boards.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, board := range boards {
+		if board.ProjectID != nil && *board.ProjectID == id {
+			return fmt.Errorf("%w: project still has boards", errs.ErrConflict)
+		}
+	}
+
+	if err := s.projects.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("project_id", id).Msg("Project deleted")
+	return nil
+}
+
+func (s *ProjectService) Members(ctx context.Context, id uuid.UUID) (members []*domain.Membership, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.Members")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.Authorize(ctx, &id, policy.ProjectsRead); err != nil {
+		return nil, err
+	}
+
+	return s.projects.Members(ctx, id)
+}
+
+// SetMember gives user the role in input, adding them to the project when
+// they aren't a member yet. Demoting the last owner fails with
+// errs.ErrConflict.
+func (s *ProjectService) SetMember(ctx context.Context, id uuid.UUID, user string, input domain.MembershipInput) (membership *domain.Membership, err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.SetMember")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.Authorize(ctx, &id, policy.MembersManage); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateUserID(user); err != nil {
+		return nil, err
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	if input.Role != domain.ProjectRoleOwner {
+		if err := s.keepOwner(ctx, id, user); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	membership = &domain.Membership{
+		ProjectID: id,
+		User:      user,
+		Role:      input.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.projects.SetMember(ctx, membership); err != nil {
+		return nil, err
+	}
+
+	// An existing member keeps their created_at; return what was stored.
+	members, err := s.projects.Members(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		if m.User == user {
+			membership = m
+		}
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("project_id", id).Str("user", user).Str("role", string(input.Role)).Msg("Project member set")
+	return membership, nil
+}
+
+// RemoveMember takes user out of the project. Removing the last owner fails
+// with errs.ErrConflict.
+func (s *ProjectService) RemoveMember(ctx context.Context, id uuid.UUID, user string) (err error) {
+	ctx, span := tracer.Start(ctx, "ProjectService.RemoveMember")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.Authorize(ctx, &id, policy.MembersManage); err != nil {
+		return err
+	}
+	if err := s.keepOwner(ctx, id, user); err != nil {
+		return err
+	}
+
+	if err := s.projects.RemoveMember(ctx, id, user); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("project_id", id).Str("user", user).Msg("Project member removed")
+	return nil
+}
+
+// keepOwner fails with errs.ErrConflict when user is the project's only
+// owner, so that taking their ownership away would leave nobody to manage
+// its members. Checking and changing are not atomic, so two owners
+// demoting each other at once can still leave the project without one.
+func (s *ProjectService) keepOwner(ctx context.Context, id uuid.UUID, user string) error {
+	members, err := s.projects.Members(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	owners, isOwner := 0, false
+	for _, m := range members {
+		if m.Role == domain.ProjectRoleOwner {
+			owners++
+			isOwner = isOwner || m.User == user
+		}
+	}
+	if isOwner && owners == 1 {
+		return fmt.Errorf("%w: %s is the project's last owner", errs.ErrConflict, user)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+)
+
+// taskEventBuffer is how many events a subscriber may fall behind before it
+// is dropped.
+const taskEventBuffer = 256
+
+// taskEvents fans task changes out to in-process subscribers. Subscribers
+// only see changes made through this instance.
+type taskEvents struct {
+	mu   sync.Mutex
+	subs map[chan domain.TaskEvent]struct{}
+}
+
+func (b *taskEvents) subscribe(ctx context.Context) <-chan domain.TaskEvent {
+	ch := make(chan domain.TaskEvent, taskEventBuffer)
+
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = map[chan domain.TaskEvent]struct{}{}
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe(ch)
+	}()
+
+	return ch
+}
+
+func (b *taskEvents) unsubscribe(ch chan domain.TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
+
+// publish never blocks. A subscriber whose buffer is full is dropped and its
+// channel closed, rather than silently missing events.
+func (b *taskEvents) publish(events ...domain.TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+subscribers:
+	for ch := range b.subs {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			default:
+				delete(b.subs, ch)
+				close(ch)
+				continue subscribers
+			}
+		}
+	}
+}
@@ -1,23 +1,785 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/notify"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
 	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/rank"
+	"github.com/rs/zerolog"
 )
 
+// errBatchRollback aborts an all-or-nothing batch transaction after one of
+// its operations failed.
+var errBatchRollback = errors.New("batch rolled back")
+
+// errRerank aborts a move whose neighbors leave no room for a new rank, so
+// the ranks can be rebalanced before it is retried.
+var errRerank = errors.New("ranks need rebalancing")
+
 type TaskService struct {
-	repo repository.TaskRepository
+	repo     repository.TaskRepository
+	boards   repository.BoardRepository
+	projects repository.ProjectRepository
+	policy   *policy.Policy
+	notifier notify.Notifier
+	events   taskEvents
+}
+
+// NewTaskService returns a service storing tasks in repo. The WIP limits of
+// the boards in boards apply to status changes, every operation checks the
+// caller's permissions in the task's project with policy, and new
+// assignees hear about their tasks through notifier.
+func NewTaskService(repo repository.TaskRepository, boards repository.BoardRepository, projects repository.ProjectRepository, policy *policy.Policy, notifier notify.Notifier) *TaskService {
+	return &TaskService{repo: repo, boards: boards, projects: projects, policy: policy, notifier: notifier}
+}
+
+func (s *TaskService) CreateTask(ctx context.Context, input domain.CreateTaskInput) (task *domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.CreateTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.authorizeCreate(ctx, input); err != nil {
+		return nil, err
+	}
+
+	task, err = createTask(ctx, s.repo, input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.publish(taskEvent(domain.TaskEventCreated, task.ID, task))
+
+	zerolog.Ctx(ctx).Info().Stringer("task_id", task.ID).Msg("Task created")
+	return task, nil
+}
+
+func (s *TaskService) GetTask(ctx context.Context, id uuid.UUID) (task *domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.GetTask")
+	defer func() { endSpan(span, err) }()
+
+	task, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.Authorize(ctx, task.ProjectID, policy.TasksRead); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// ListTasks returns the tasks the caller may read.
+func (s *TaskService) ListTasks(ctx context.Context) (tasks []*domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.ListTasks")
+	defer func() { endSpan(span, err) }()
+
+	var filter domain.TaskFilter
+	if err := s.policy.Restrict(ctx, &filter); err != nil {
+		return nil, err
+	}
+	if !filter.RestrictProjects {
+		return s.repo.List(ctx)
+	}
+
+	return s.findTasks(ctx, filter)
+}
+
+// FindTasks returns the tasks matching filter that the caller may read,
+// newest first.
+func (s *TaskService) FindTasks(ctx context.Context, filter domain.TaskFilter) (tasks []*domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.FindTasks")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.policy.Restrict(ctx, &filter); err != nil {
+		return nil, err
+	}
+
+	return s.findTasks(ctx, filter)
+}
+
+func (s *TaskService) findTasks(ctx context.Context, filter domain.TaskFilter) (tasks []*domain.Task, err error) {
+	err = s.repo.Find(ctx, filter, func(task *domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// TaskStats counts every task, whoever may read it; it feeds the metrics.
+func (s *TaskService) TaskStats(ctx context.Context) (stats *domain.TaskStats, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.TaskStats")
+	defer func() { endSpan(span, err) }()
+
+	return s.repo.Stats(ctx, time.Now())
+}
+
+func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, input domain.UpdateTaskInput) (task *domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.UpdateTask")
+	defer func() { endSpan(span, err) }()
+
+	err = s.repo.WithTx(ctx, func(tx repository.TaskRepository) error {
+		task, err = s.updateTask(ctx, tx, id, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.publish(taskEvent(domain.TaskEventUpdated, id, task))
+
+	zerolog.Ctx(ctx).Info().Stringer("task_id", id).Msg("Task updated")
+	return task, nil
+}
+
+// PatchTask loads the task, passes its JSON representation to patch and
+// stores the result. Unlike UpdateTask this can clear optional fields such as
+// due_date. The id, project, rank, people and timestamps are managed by the
+// server; a patch that changes id, project_id, rank, assignees, watchers or
+// created_at is rejected and updated_at is always overwritten. WIP limits
+// apply as in UpdateTask, unless overrideWIPLimit is set.
+func (s *TaskService) PatchTask(ctx context.Context, id uuid.UUID, patch func(doc []byte) ([]byte, error), overrideWIPLimit bool) (_ *domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.PatchTask")
+	defer func() { endSpan(span, err) }()
+
+	var updated domain.Task
+	err = s.repo.WithTx(ctx, func(tx repository.TaskRepository) error {
+		task, err := tx.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.policy.Authorize(ctx, task.ProjectID, policy.TasksWrite); err != nil {
+			return err
+		}
+
+		doc, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+
+		patched, err := patch(doc)
+		if err != nil {
+			return err
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(patched))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&updated); err != nil {
+			return fmt.Errorf("%w: %v", errs.ErrInvalidInput, err)
+		}
+
+		if updated.ID != task.ID || !updated.CreatedAt.Equal(task.CreatedAt) {
+			return fmt.Errorf("%w: id and created_at cannot be changed", errs.ErrInvalidInput)
+		}
+		if !equalProject(updated.ProjectID, task.ProjectID) {
+			return fmt.Errorf("%w: project_id cannot be changed", errs.ErrInvalidInput)
+		}
+		if updated.Rank != task.Rank {
+			return fmt.Errorf("%w: rank cannot be changed, move the task instead", errs.ErrInvalidInput)
+		}
+		if !slices.Equal(updated.Assignees, task.Assignees) || !slices.Equal(updated.Watchers, task.Watchers) {
+			return fmt.Errorf("%w: assignees and watchers cannot be patched, use their endpoints instead", errs.ErrInvalidInput)
+		}
+
+		if err := (domain.CreateTaskInput{Title: updated.Title, Status: updated.Status, Priority: updated.Priority}).Validate(); err != nil {
+			return err
+		}
+		if updated.Status == "" {
+			return fmt.Errorf("%w: status is required", errs.ErrInvalidInput)
+		}
+		if updated.Priority == "" {
+			return fmt.Errorf("%w: priority is required", errs.ErrInvalidInput)
+		}
+
+		if !overrideWIPLimit {
+			if err := s.checkWIPLimit(ctx, tx, task, updated.Status); err != nil {
+				return err
+			}
+		}
+
+		updated.UpdatedAt = time.Now()
+
+		return tx.Update(ctx, &updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.publish(taskEvent(domain.TaskEventUpdated, id, &updated))
+
+	zerolog.Ctx(ctx).Info().Stringer("task_id", id).Msg("Task patched")
+	return &updated, nil
+}
+
+// MoveTask gives the task a rank that places it as input asks. When the
+// neighbors' ranks leave no room, every rank is rebalanced and the move is
+// tried once more.
+func (s *TaskService) MoveTask(ctx context.Context, id uuid.UUID, input domain.MoveTaskInput) (task *domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.MoveTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := input.Validate(id); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = s.repo.WithTx(ctx, func(tx repository.TaskRepository) error {
+			task, err = s.moveTask(ctx, tx, id, input)
+			return err
+		})
+		if !errors.Is(err, errRerank) || attempt > 0 {
+			break
+		}
+
+		zerolog.Ctx(ctx).Info().Stringer("task_id", id).Msg("No room to move task, rebalancing ranks")
+		if err := s.rerank(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.publish(taskEvent(domain.TaskEventUpdated, id, task))
+
+	zerolog.Ctx(ctx).Info().Stringer("task_id", id).Str("rank", task.Rank).Msg("Task moved")
+	return task, nil
+}
+
+// AddTaskUser makes user an assignee or a watcher of the task, per
+// relation. Adding a user who already is one changes nothing.
+//
+// Every change is recorded in the task's history and published as an
+// updated event. New assignees are notified, unless they assigned
+// themselves.
+func (s *TaskService) AddTaskUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (task *domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.AddTaskUser")
+	defer func() { endSpan(span, err) }()
+
+	return s.changeTaskUser(ctx, id, relation, user, true)
+}
+
+// RemoveTaskUser undoes AddTaskUser. Removing a user who isn't one changes
+// nothing.
+//
+// Both need tasks:write, except that users who may read the task can watch
+// it, or stop watching it, themselves.
+func (s *TaskService) RemoveTaskUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string) (task *domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.RemoveTaskUser")
+	defer func() { endSpan(span, err) }()
+
+	return s.changeTaskUser(ctx, id, relation, user, false)
+}
+
+func (s *TaskService) changeTaskUser(ctx context.Context, id uuid.UUID, relation domain.TaskRelation, user string, add bool) (task *domain.Task, err error) {
+	if err := domain.ValidateUserID(user); err != nil {
+		return nil, err
+	}
+
+	permission := policy.TasksWrite
+	if relation == domain.TaskRelationWatcher && user == policy.User(ctx) {
+		permission = policy.TasksRead
+	}
+
+	changed := false
+	err = s.repo.WithTx(ctx, func(tx repository.TaskRepository) error {
+		current, err := tx.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.policy.Authorize(ctx, current.ProjectID, permission); err != nil {
+			return err
+		}
+
+		change := tx.RemoveUser
+		if add {
+			change = tx.AddUser
+		}
+		if changed, err = change(ctx, id, relation, user); err != nil {
+			return err
+		}
+
+		if task, err = tx.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		task.UpdatedAt = time.Now()
+		if err := tx.Update(ctx, task); err != nil {
+			return err
+		}
+
+		entry := &domain.TaskHistoryEntry{
+			ID:        uuid.New(),
+			TaskID:    id,
+			Change:    domain.TaskChangeUserRemoved,
+			Relation:  relation,
+			User:      user,
+			Actor:     policy.User(ctx),
+			CreatedAt: task.UpdatedAt,
+		}
+		if add {
+			entry.Change = domain.TaskChangeUserAdded
+		}
+		return tx.AddHistory(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return task, nil
+	}
+
+	s.events.publish(taskEvent(domain.TaskEventUpdated, id, task))
+
+	event := zerolog.Ctx(ctx).Info().Stringer("task_id", id).Str("relation", string(relation)).Str("user", user)
+	if add {
+		event.Msg("User added to task")
+	} else {
+		event.Msg("User removed from task")
+	}
+
+	if add && relation == domain.TaskRelationAssignee && user != policy.User(ctx) {
+		s.notify(ctx, domain.Notification{
+			Kind:      domain.NotificationAssigned,
+			User:      user,
+			Actor:     policy.User(ctx),
+			Task:      task,
+			CreatedAt: task.UpdatedAt,
+		})
+	}
+	return task, nil
+}
+
+// notify hands n to the notifier. The change it is about is already
+// stored, so a failure is only logged.
+func (s *TaskService) notify(ctx context.Context, n domain.Notification) {
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("user", n.User).Stringer("task_id", n.Task.ID).Msg("Failed to send notification")
+	}
+}
+
+// TaskHistory returns the history of the task, oldest first.
+func (s *TaskService) TaskHistory(ctx context.Context, id uuid.UUID) (entries []*domain.TaskHistoryEntry, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.TaskHistory")
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.GetTask(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.History(ctx, id)
+}
+
+// RebalanceRanks spreads the ranks out again when a task has none or the
+// longest rank is over maxLength. It reports whether it did.
+func (s *TaskService) RebalanceRanks(ctx context.Context, maxLength int) (rebalanced bool, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.RebalanceRanks")
+	defer func() { endSpan(span, err) }()
+
+	stats, err := s.repo.RankStats(ctx)
+	if err != nil {
+		return false, err
+	}
+	if stats.Unranked == 0 && stats.MaxLength <= maxLength {
+		return false, nil
+	}
+
+	if err := s.rerank(ctx); err != nil {
+		return false, err
+	}
+
+	zerolog.Ctx(ctx).Info().Int("unranked", stats.Unranked).Int("max_length", stats.MaxLength).Msg("Task ranks rebalanced")
+	return true, nil
+}
+
+// KeepRanksBalanced runs RebalanceRanks every interval until ctx is
+// cancelled, so ranks grown long by repeated moves into the same gap are
+// shortened before they get in the way.
+func (s *TaskService) KeepRanksBalanced(ctx context.Context, interval time.Duration, maxLength int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RebalanceRanks(ctx, maxLength); err != nil && ctx.Err() == nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to rebalance task ranks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TaskService) rerank(ctx context.Context) error {
+	return s.repo.Rerank(ctx, func(n int) ([]string, error) {
+		return rank.N("", "", n)
+	})
+}
+
+func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.DeleteTask")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.deleteTask(ctx, s.repo, id); err != nil {
+		return err
+	}
+
+	s.events.publish(taskEvent(domain.TaskEventDeleted, id, nil))
+
+	zerolog.Ctx(ctx).Info().Stringer("task_id", id).Msg("Task deleted")
+	return nil
+}
+
+// ExportTasks streams every task the caller may read to fn; see
+// repository.TaskRepository.ForEach.
+func (s *TaskService) ExportTasks(ctx context.Context, fn func(task *domain.Task) error) (err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.ExportTasks")
+	defer func() { endSpan(span, err) }()
+
+	var filter domain.TaskFilter
+	if err := s.policy.Restrict(ctx, &filter); err != nil {
+		return err
+	}
+	if !filter.RestrictProjects {
+		return s.repo.ForEach(ctx, fn)
+	}
+
+	return s.repo.Find(ctx, filter, fn)
+}
+
+// ImportTasks validates every row and, unless dryRun is set or a row is
+// invalid, creates all of them in one bulk insert. Nothing is written when
+// any row fails validation. Rows naming a project that doesn't exist are
+// invalid; when the caller may not add tasks to a row's project the whole
+// import is refused, as CreateTask would refuse that task.
+func (s *TaskService) ImportTasks(ctx context.Context, rows []domain.ImportRow, dryRun bool) (_ *domain.ImportResult, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.ImportTasks")
+	defer func() { endSpan(span, err) }()
+
+	if err := policy.RequireScope(ctx, policy.ScopeFor(policy.TasksWrite)); err != nil {
+		return nil, err
+	}
+	if err := s.policy.SignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &domain.ImportResult{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: []domain.ImportLineError{},
+	}
+
+	now := time.Now()
+	tasks := make([]*domain.Task, 0, len(rows))
+
+	// Most files put every row in the same few projects, so each is only
+	// checked once.
+	authorized := make(map[uuid.UUID]error)
+	authorize := func(input domain.CreateTaskInput) error {
+		var key uuid.UUID
+		if input.ProjectID != nil {
+			key = *input.ProjectID
+		}
+		err, ok := authorized[key]
+		if !ok {
+			err = s.authorizeCreate(ctx, input)
+			authorized[key] = err
+		}
+		return err
+	}
+
+	for _, row := range rows {
+		err := row.Err
+		if err == nil {
+			err = row.Input.Validate()
+		}
+		if err == nil {
+			if err = authorize(row.Input); err != nil && !errors.Is(err, errs.ErrInvalidInput) {
+				return nil, err
+			}
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, domain.ImportLineError{Line: row.Line, Error: err.Error()})
+			continue
+		}
+
+		input := row.Input
+		if input.Status == "" {
+			input.Status = domain.TaskStatusTodo
+		}
+		if input.Priority == "" {
+			input.Priority = domain.DefaultTaskPriority
+		}
+
+		tasks = append(tasks, &domain.Task{
+			ID:          uuid.New(),
+			ProjectID:   input.ProjectID,
+			Title:       input.Title,
+			Description: input.Description,
+			Status:      input.Status,
+			Priority:    input.Priority,
+			DueDate:     input.DueDate,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	if dryRun || len(result.Errors) > 0 || len(tasks) == 0 {
+		return result, nil
+	}
+
+	// Imported tasks go to the end of the rank order, in file order.
+	last, err := s.repo.LastRank(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ranks, err := rank.N(last, "", len(tasks))
+	if err != nil {
+		return nil, err
+	}
+	for i, task := range tasks {
+		task.Rank = ranks[i]
+	}
+
+	if err := s.repo.CreateMany(ctx, tasks); err != nil {
+		return nil, err
+	}
+	result.Imported = len(tasks)
+
+	events := make([]domain.TaskEvent, len(tasks))
+	for i, task := range tasks {
+		events[i] = taskEvent(domain.TaskEventCreated, task.ID, task)
+	}
+	s.events.publish(events...)
+
+	zerolog.Ctx(ctx).Info().Int("imported", result.Imported).Msg("Tasks imported")
+
+	return result, nil
+}
+
+// BatchTasks applies every operation in input inside a single transaction.
+// Each operation runs in its own savepoint so a failure is reported for that
+// item only; in all-or-nothing mode any failure rolls back the whole batch.
+func (s *TaskService) BatchTasks(ctx context.Context, input domain.BatchInput) (_ *domain.BatchResult, err error) {
+	ctx, span := tracer.Start(ctx, "TaskService.BatchTasks")
+	defer func() { endSpan(span, err) }()
+
+	if input.Mode == "" {
+		input.Mode = domain.BatchModeAllOrNothing
+	}
+	if input.Mode != domain.BatchModeAllOrNothing && input.Mode != domain.BatchModeBestEffort {
+		return nil, fmt.Errorf("%w: unknown batch mode %q", errs.ErrInvalidInput, input.Mode)
+	}
+	if len(input.Operations) == 0 {
+		return nil, fmt.Errorf("%w: batch has no operations", errs.ErrInvalidInput)
+	}
+	if len(input.Operations) > domain.MaxBatchOperations {
+		return nil, fmt.Errorf("%w: batch exceeds %d operations", errs.ErrInvalidInput, domain.MaxBatchOperations)
+	}
+
+	result := &domain.BatchResult{
+		Mode:    input.Mode,
+		Results: make([]domain.BatchItemResult, len(input.Operations)),
+	}
+
+	err = s.repo.WithTx(ctx, func(tx repository.TaskRepository) error {
+		failed := false
+
+		for i, op := range input.Operations {
+			item := domain.BatchItemResult{Index: i, Op: op.Op}
+
+			err := tx.WithTx(ctx, func(sp repository.TaskRepository) error {
+				task, err := s.applyBatchOperation(ctx, sp, op)
+				item.Task = task
+				return err
+			})
+			if err != nil {
+				item.Err = err
+				item.Task = nil
+				failed = true
+			} else {
+				item.Applied = true
+				item.ID = op.ID
+				if item.Task != nil {
+					item.ID = &item.Task.ID
+				}
+			}
+
+			result.Results[i] = item
+		}
+
+		if failed && input.Mode == domain.BatchModeAllOrNothing {
+			return errBatchRollback
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		result.Committed = true
+	case errors.Is(err, errBatchRollback):
+		// Nothing was written, so no item may look created or updated.
+		result.Committed = false
+		for i := range result.Results {
+			result.Results[i].Applied = false
+			result.Results[i].ID = nil
+			result.Results[i].Task = nil
+		}
+	default:
+		return nil, err
+	}
+
+	if result.Committed {
+		s.events.publish(batchEvents(input.Operations, result.Results)...)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("mode", string(result.Mode)).
+		Int("operations", len(result.Results)).
+		Bool("committed", result.Committed).
+		Msg("Batch processed")
+
+	return result, nil
+}
+
+// Subscribe returns a channel receiving every task change committed through
+// this service from now on, for the tasks the caller may read. Which
+// projects those are is decided once, when subscribing; deletions carry no
+// task and are always passed on. The channel is closed when ctx is done, or
+// early if the subscriber falls too far behind.
+func (s *TaskService) Subscribe(ctx context.Context) (<-chan domain.TaskEvent, error) {
+	projects, restricted, err := s.policy.Permitted(ctx, policy.TasksRead)
+	if err != nil {
+		return nil, err
+	}
+
+	events := s.events.subscribe(ctx)
+	if !restricted {
+		return events, nil
+	}
+
+	readable := make(chan domain.TaskEvent)
+	go func() {
+		defer close(readable)
+		for event := range events {
+			if event.Task != nil && (event.Task.ProjectID == nil || !slices.Contains(projects, *event.Task.ProjectID)) {
+				continue
+			}
+			select {
+			case readable <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return readable, nil
+}
+
+func taskEvent(eventType domain.TaskEventType, id uuid.UUID, task *domain.Task) domain.TaskEvent {
+	return domain.TaskEvent{Type: eventType, TaskID: id, Task: task, Time: time.Now()}
+}
+
+func batchEvents(ops []domain.BatchOperation, results []domain.BatchItemResult) []domain.TaskEvent {
+	var events []domain.TaskEvent
+	for i, item := range results {
+		if !item.Applied || item.ID == nil {
+			continue
+		}
+
+		switch ops[i].Op {
+		case domain.BatchOpCreate:
+			events = append(events, taskEvent(domain.TaskEventCreated, *item.ID, item.Task))
+		case domain.BatchOpUpdate:
+			events = append(events, taskEvent(domain.TaskEventUpdated, *item.ID, item.Task))
+		case domain.BatchOpDelete:
+			events = append(events, taskEvent(domain.TaskEventDeleted, *item.ID, nil))
+		}
+	}
+	return events
+}
+
+func (s *TaskService) applyBatchOperation(ctx context.Context, repo repository.TaskRepository, op domain.BatchOperation) (*domain.Task, error) {
+	switch op.Op {
+	case domain.BatchOpCreate:
+		var input domain.CreateTaskInput
+		if err := json.Unmarshal(op.Data, &input); err != nil {
+			return nil, fmt.Errorf("%w: %v", errs.ErrInvalidInput, err)
+		}
+		if err := s.authorizeCreate(ctx, input); err != nil {
+			return nil, err
+		}
+		return createTask(ctx, repo, input)
+
+	case domain.BatchOpUpdate:
+		if op.ID == nil {
+			return nil, fmt.Errorf("%w: id is required for update", errs.ErrInvalidInput)
+		}
+		var input domain.UpdateTaskInput
+		if err := json.Unmarshal(op.Data, &input); err != nil {
+			return nil, fmt.Errorf("%w: %v", errs.ErrInvalidInput, err)
+		}
+		return s.updateTask(ctx, repo, *op.ID, input)
+
+	case domain.BatchOpDelete:
+		if op.ID == nil {
+			return nil, fmt.Errorf("%w: id is required for delete", errs.ErrInvalidInput)
+		}
+		return nil, s.deleteTask(ctx, repo, *op.ID)
+	}
+
+	return nil, fmt.Errorf("%w: unknown operation %q", errs.ErrInvalidInput, op.Op)
 }
 
-func NewTaskService(repo repository.TaskRepository) *TaskService {
-	return &TaskService{repo: repo}
+// authorizeCreate checks that the caller may add tasks to the project input
+// puts the task in, and that the project exists.
+func (s *TaskService) authorizeCreate(ctx context.Context, input domain.CreateTaskInput) error {
+	if err := s.policy.Authorize(ctx, input.ProjectID, policy.TasksWrite); err != nil {
+		return err
+	}
+	if input.ProjectID == nil {
+		return nil
+	}
+
+	_, err := s.projects.GetByID(ctx, *input.ProjectID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return fmt.Errorf("%w: project %s not found", errs.ErrInvalidInput, *input.ProjectID)
+	}
+	return err
 }
 
-func (s *TaskService) CreateTask(ctx context.Context, input domain.CreateTaskInput) (*domain.Task, error) {
+func (s *TaskService) deleteTask(ctx context.Context, repo repository.TaskRepository, id uuid.UUID) error {
+	task, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.policy.Authorize(ctx, task.ProjectID, policy.TasksDelete); err != nil {
+		return err
+	}
+
+	return repo.Delete(ctx, id)
+}
+
+func createTask(ctx context.Context, repo repository.TaskRepository, input domain.CreateTaskInput) (*domain.Task, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 
 	// Set default status if not provided
@@ -25,36 +787,58 @@ func (s *TaskService) CreateTask(ctx context.Context, input domain.CreateTaskInp
 		input.Status = domain.TaskStatusTodo
 	}
 
+	if input.Priority == "" {
+		input.Priority = domain.DefaultTaskPriority
+	}
+
+	// New tasks go to the end of the rank order
+	last, err := repo.LastRank(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key, err := rank.Between(last, "")
+	if err != nil {
+		return nil, err
+	}
+
 	task := &domain.Task{
 		ID:          uuid.New(),
+		ProjectID:   input.ProjectID,
 		Title:       input.Title,
 		Description: input.Description,
 		Status:      input.Status,
+		Priority:    input.Priority,
+		Rank:        key,
 		DueDate:     input.DueDate,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
-	if err := s.repo.Create(ctx, task); err != nil {
+	if err := repo.Create(ctx, task); err != nil {
 		return nil, err
 	}
 
 	return task, nil
 }
 
-func (s *TaskService) GetTask(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
-	return s.repo.GetByID(ctx, id)
-}
-
-func (s *TaskService) ListTasks(ctx context.Context) ([]*domain.Task, error) {
-	return s.repo.List(ctx)
-}
+func (s *TaskService) updateTask(ctx context.Context, repo repository.TaskRepository, id uuid.UUID, input domain.UpdateTaskInput) (*domain.Task, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
 
-func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, input domain.UpdateTaskInput) (*domain.Task, error) {
-	task, err := s.repo.GetByID(ctx, id)
+	task, err := repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.policy.Authorize(ctx, task.ProjectID, policy.TasksWrite); err != nil {
+		return nil, err
+	}
+
+	if input.Status != nil && !input.OverrideWIPLimit {
+		if err := s.checkWIPLimit(ctx, repo, task, *input.Status); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update fields if provided
 	if input.Title != nil {
@@ -69,19 +853,163 @@ func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, input domain
 		task.Status = *input.Status
 	}
 
+	if input.Priority != nil {
+		task.Priority = *input.Priority
+	}
+
 	if input.DueDate != nil {
 		task.DueDate = input.DueDate
 	}
 
 	task.UpdatedAt = time.Now()
 
-	if err := s.repo.Update(ctx, task); err != nil {
+	if err := repo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// checkWIPLimit fails with errs.ErrConflict when moving the task to status
+// to would take a column of one of its project's boards over its WIP limit.
+// A column counts the tasks of the board's project, or those in no project.
+// Moves within a column are always allowed. repo must be bound to a
+// transaction that also stores the move: counting locks the project until
+// it ends, so moves racing into the same column can't overshoot its limit.
+func (s *TaskService) checkWIPLimit(ctx context.Context, repo repository.TaskRepository, task *domain.Task, to domain.TaskStatus) error {
+	if task.Status == to {
+		return nil
+	}
+
+	boards, err := s.boards.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	locked := false
+	for _, board := range boards {
+		if !equalProject(board.ProjectID, task.ProjectID) {
+			continue
+		}
+		column := board.ColumnFor(to)
+		if column == nil || column.WIPLimit == 0 || slices.Contains(column.Statuses, task.Status) {
+			continue
+		}
+
+		if !locked {
+			if err := repo.LockProject(ctx, task.ProjectID); err != nil {
+				return err
+			}
+			locked = true
+		}
+		n, err := countTasks(ctx, repo, board.TaskFilter(*column))
+		if err != nil {
+			return err
+		}
+		if n >= column.WIPLimit {
+			return fmt.Errorf("%w: column %q of board %q is at its WIP limit of %d", errs.ErrConflict, column.Name, board.Name, column.WIPLimit)
+		}
+	}
+
+	return nil
+}
+
+// moveTask stores a rank for the task between the ranks input points at. It
+// returns errRerank when one of them is missing or there is no room between
+// them. The caller must be able to change the task and read the tasks it is
+// placed next to.
+func (s *TaskService) moveTask(ctx context.Context, repo repository.TaskRepository, id uuid.UUID, input domain.MoveTaskInput) (*domain.Task, error) {
+	task, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.Authorize(ctx, task.ProjectID, policy.TasksWrite); err != nil {
+		return nil, err
+	}
+
+	reference := func(ref uuid.UUID) (*domain.Task, error) {
+		t, err := repo.GetByID(ctx, ref)
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil, fmt.Errorf("%w: task %s not found", errs.ErrInvalidInput, ref)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := s.policy.Authorize(ctx, t.ProjectID, policy.TasksRead); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+	// neighbor returns the rank next to t on the other side from the
+	// reference, or "" when t is at that end of the order.
+	neighbor := func(t *domain.Task, before bool) (string, bool, error) {
+		key, err := repo.NeighborRank(ctx, t, before, id)
+		if errors.Is(err, errs.ErrNotFound) {
+			return "", true, nil
+		}
+		return key, false, err
+	}
+
+	var lower, upper string
+	var lowerOpen, upperOpen bool
+	switch {
+	case input.After != nil && input.Before != nil:
+		after, err := reference(*input.After)
+		if err != nil {
+			return nil, err
+		}
+		before, err := reference(*input.Before)
+		if err != nil {
+			return nil, err
+		}
+		if after.Rank > before.Rank {
+			return nil, fmt.Errorf("%w: task %s comes after task %s", errs.ErrInvalidInput, after.ID, before.ID)
+		}
+		lower, upper = after.Rank, before.Rank
+	case input.After != nil:
+		after, err := reference(*input.After)
+		if err != nil {
+			return nil, err
+		}
+		lower = after.Rank
+		if upper, upperOpen, err = neighbor(after, false); err != nil {
+			return nil, err
+		}
+	default:
+		before, err := reference(*input.Before)
+		if err != nil {
+			return nil, err
+		}
+		upper = before.Rank
+		if lower, lowerOpen, err = neighbor(before, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// An empty rank is only an open end when there is no task on that side.
+	if (lower == "" && !lowerOpen) || (upper == "" && !upperOpen) {
+		return nil, errRerank
+	}
+	key, err := rank.Between(lower, upper)
+	if errors.Is(err, rank.ErrNoRoom) || errors.Is(err, rank.ErrInvalidKey) {
+		return nil, errRerank
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	task.Rank = key
+	task.UpdatedAt = time.Now()
+	if err := repo.Update(ctx, task); err != nil {
 		return nil, err
 	}
 
 	return task, nil
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+func equalProject(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/services")
+
+// endSpan ends a service span, marking it failed when err is set. Use it
+// with a named error result: defer func() { endSpan(span, err) }().
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/domain"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/errors"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/policy"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+type ViewService struct {
+	views  repository.ViewRepository
+	tasks  repository.TaskRepository
+	policy *policy.Policy
+}
+
+// NewViewService returns a service for the views in views. Running a view
+// only finds the tasks in tasks that policy lets the caller read.
+func NewViewService(views repository.ViewRepository, tasks repository.TaskRepository, policy *policy.Policy) *ViewService {
+	return &ViewService{views: views, tasks: tasks, policy: policy}
+}
+
+func (s *ViewService) CreateView(ctx context.Context, input domain.CreateViewInput) (view *domain.View, err error) {
+	ctx, span := tracer.Start(ctx, "ViewService.CreateView")
+	defer func() { endSpan(span, err) }()
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	view = &domain.View{
+		ID:             uuid.New(),
+		Name:           strings.TrimSpace(input.Name),
+		Owner:          input.Owner,
+		Team:           input.Team,
+		ViewDefinition: input.ViewDefinition,
+		SchemaVersion:  domain.ViewSchemaVersion,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.views.Create(ctx, view); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("view_id", view.ID).Str("owner", view.Owner).Msg("View created")
+	return view, nil
+}
+
+func (s *ViewService) GetView(ctx context.Context, id uuid.UUID) (view *domain.View, err error) {
+	ctx, span := tracer.Start(ctx, "ViewService.GetView")
+	defer func() { endSpan(span, err) }()
+
+	return s.views.GetByID(ctx, id)
+}
+
+// ListViews returns the views owner saved and those shared with team.
+func (s *ViewService) ListViews(ctx context.Context, owner, team string) (views []*domain.View, err error) {
+	ctx, span := tracer.Start(ctx, "ViewService.ListViews")
+	defer func() { endSpan(span, err) }()
+
+	return s.views.List(ctx, owner, team)
+}
+
+// UpdateView applies input and checks the resulting definition, which also
+// clears a problem found by RevalidateViews once it is fixed.
+func (s *ViewService) UpdateView(ctx context.Context, id uuid.UUID, input domain.UpdateViewInput) (view *domain.View, err error) {
+	ctx, span := tracer.Start(ctx, "ViewService.UpdateView")
+	defer func() { endSpan(span, err) }()
+
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	view, err = s.views.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		view.Name = strings.TrimSpace(*input.Name)
+	}
+
+	if input.Team != nil {
+		view.Team = *input.Team
+	}
+
+	if input.Filter != nil {
+		view.Filter = *input.Filter
+	}
+
+	if input.Sort != nil {
+		view.Sort = *input.Sort
+	}
+
+	if input.Columns != nil {
+		view.Columns = *input.Columns
+	}
+
+	if err := view.Validate(); err != nil {
+		return nil, err
+	}
+	view.SchemaVersion = domain.ViewSchemaVersion
+	view.Problem = ""
+	view.UpdatedAt = time.Now()
+
+	if err := s.views.Update(ctx, view); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("view_id", id).Msg("View updated")
+	return view, nil
+}
+
+func (s *ViewService) DeleteView(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := tracer.Start(ctx, "ViewService.DeleteView")
+	defer func() { endSpan(span, err) }()
+
+	if err := s.views.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().Stringer("view_id", id).Msg("View deleted")
+	return nil
+}
+
+// RunView returns the view and those of its tasks the caller may read, in
+// the view's order. A view whose definition no longer validates fails with
+// errs.ErrInvalidInput until it is updated.
+func (s *ViewService) RunView(ctx context.Context, id uuid.UUID) (view *domain.View, tasks []*domain.Task, err error) {
+	ctx, span := tracer.Start(ctx, "ViewService.RunView")
+	defer func() { endSpan(span, err) }()
+
+	view, err = s.views.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := view.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	filter := view.TaskFilter()
+	if err := s.policy.Restrict(ctx, &filter); err != nil {
+		return nil, nil, err
+	}
+
+	err = s.tasks.Find(ctx, filter, func(task *domain.Task) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	view.SortTasks(tasks)
+
+	return view, tasks, nil
+}
+
+// RevalidateViews checks the views saved under an older
+// domain.ViewSchemaVersion against the current one and records what is
+// wrong with those that no longer validate. It returns how many did not.
+func (s *ViewService) RevalidateViews(ctx context.Context) (broken int, err error) {
+	ctx, span := tracer.Start(ctx, "ViewService.RevalidateViews")
+	defer func() { endSpan(span, err) }()
+
+	views, err := s.views.ListOutdated(ctx, domain.ViewSchemaVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, view := range views {
+		view.Problem = ""
+		if err := view.Validate(); err != nil {
+			view.Problem = strings.TrimPrefix(err.Error(), errs.ErrInvalidInput.Error()+": ")
+			broken++
+			zerolog.Ctx(ctx).Warn().Stringer("view_id", view.ID).Str("problem", view.Problem).Msg("Saved view no longer valid")
+		}
+		view.SchemaVersion = domain.ViewSchemaVersion
+
+		if err := s.views.Update(ctx, view); err != nil {
+			return broken, err
+		}
+	}
+
+	return broken, nil
+}
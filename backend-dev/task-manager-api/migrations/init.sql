@@ -3,10 +3,186 @@ CREATE TABLE IF NOT EXISTS tasks (
     title VARCHAR(255) NOT NULL,
     description TEXT,
     status VARCHAR(20) NOT NULL,
+    priority VARCHAR(2) NOT NULL DEFAULT 'P2',
+    rank TEXT COLLATE "C" NOT NULL DEFAULT '',
     due_date TIMESTAMP NULL,
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP NOT NULL
 );
 
 CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
-CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at);
+
+-- Databases created before tasks had a priority and rank; existing tasks
+-- get ranks from the next rebalance.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority VARCHAR(2) NOT NULL DEFAULT 'P2';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank TEXT COLLATE "C" NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_rank ON tasks(rank, created_at, id);
+
+-- Assignees and watchers of tasks; relation is 'assignee' or 'watcher'.
+CREATE TABLE IF NOT EXISTS task_people (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    relation VARCHAR(20) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (task_id, relation, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_people_user ON task_people(user_id, relation);
+
+-- Changes to tasks, for now only to their assignees and watchers. change is
+-- 'user_added' or 'user_removed', relation 'assignee' or 'watcher'; actor is
+-- empty for changes made without a user. seq keeps entries in the order
+-- they were recorded.
+CREATE TABLE IF NOT EXISTS task_history (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    change VARCHAR(32) NOT NULL,
+    relation VARCHAR(20) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_history_task ON task_history(task_id, seq);
+
+CREATE TABLE IF NOT EXISTS projects (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- role is 'owner', 'maintainer', 'member' or 'viewer'.
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_members_user ON project_members(user_id);
+
+-- Tasks created before projects belong to none. A project can't be deleted
+-- while it has tasks.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id UUID NULL REFERENCES projects(id);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id UUID PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Only a SHA-256 hash of each API token is stored. scopes is a JSON array
+-- of 'tasks:read', 'tasks:write' and 'admin'; revoked tokens are kept for
+-- their owner's listing.
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner VARCHAR(255) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    scopes JSONB NOT NULL,
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_owner ON api_tokens(owner);
+
+-- key is "<user>/<Idempotency-Key>", empty user for anonymous requests.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(512) PRIMARY KEY,
+    fingerprint CHAR(64) NOT NULL,
+    status_code INT NULL,
+    content_type VARCHAR(255) NULL,
+    response_body BYTEA NULL,
+    locked_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(512);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+
+-- idle_after is when a bucket will be full again; the API deletes buckets
+-- past it in the background.
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key VARCHAR(512) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    idle_after TIMESTAMP NOT NULL
+);
+
+ALTER TABLE rate_limit_buckets ADD COLUMN IF NOT EXISTS idle_after TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_idle_after ON rate_limit_buckets(idle_after);
+
+CREATE TABLE IF NOT EXISTS saved_views (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner VARCHAR(255) NOT NULL,
+    team VARCHAR(255) NOT NULL DEFAULT '',
+    definition JSONB NOT NULL,
+    schema_version INT NOT NULL,
+    problem TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_views_owner ON saved_views(owner);
+CREATE INDEX IF NOT EXISTS idx_saved_views_team ON saved_views(team) WHERE team <> '';
+
+CREATE TABLE IF NOT EXISTS boards (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    columns JSONB NOT NULL,
+    version INT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Boards created before they had a project belong to none and show the
+-- tasks in no project. A project can't be deleted while it has boards.
+ALTER TABLE boards ADD COLUMN IF NOT EXISTS project_id UUID NULL REFERENCES projects(id);
+
+CREATE INDEX IF NOT EXISTS idx_boards_project_id ON boards(project_id);
+
+CREATE TABLE IF NOT EXISTS board_versions (
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    columns JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (board_id, version)
+);
+
+-- Bump postgres.SchemaVersion together with any schema change above and
+-- record the new version here; /readyz fails while the database is behind.
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version) VALUES (1) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (2) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (3) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (4) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (5) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (6) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (7) ON CONFLICT DO NOTHING;
+-- Idempotency keys stored before they were scoped to a user must not be
+-- replayed to anyone; they are dropped once, when upgrading to version 8.
+DELETE FROM idempotency_keys WHERE NOT EXISTS (SELECT 1 FROM schema_migrations WHERE version >= 8);
+INSERT INTO schema_migrations (version) VALUES (8) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (9) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (10) ON CONFLICT DO NOTHING;
+INSERT INTO schema_migrations (version) VALUES (11) ON CONFLICT DO NOTHING;
@@ -0,0 +1,31 @@
+package client
+
+import "net/http"
+
+// Authenticator adds credentials to every outgoing request, including
+// retries.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to Authenticator, e.g. to fetch a
+// fresh token from a token source.
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken sends token in an Authorization header.
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// APIKey sends an API token issued by POST /api/v1/tokens. The server
+// takes API tokens as bearer tokens, just like JWTs.
+func APIKey(key string) Authenticator {
+	return BearerToken(key)
+}
@@ -0,0 +1,219 @@
+// Package client is a typed Go client for the task manager REST API.
+//
+//	c, err := client.New("https://tasks.example.com", client.WithAuth(client.BearerToken(token)))
+//	task, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Write docs"})
+//	if errors.Is(err, client.ErrInvalidInput) { ... }
+//
+// Calls that are safe to repeat are retried with exponential backoff on
+// network errors, 429 and 5xx responses. Creates are made safe to retry by
+// sending an Idempotency-Key.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultUserAgent = "task-manager-go-client"
+
+type Client struct {
+	baseURL   *url.URL
+	http      *http.Client
+	auth      Authenticator
+	retry     RetryPolicy
+	userAgent string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client, for custom transports or
+// timeouts. The default is http.DefaultClient; deadlines should normally
+// come from the context passed to each call.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithAuth authenticates every request with auth.
+func WithAuth(auth Authenticator) Option {
+	return func(c *Client) { c.auth = auth }
+}
+
+// WithRetry replaces DefaultRetryPolicy. Use NoRetry to turn retries off.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New returns a client for the API served at baseURL, e.g.
+// "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("client: base URL %q must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:   u,
+		http:      http.DefaultClient,
+		retry:     DefaultRetryPolicy,
+		userAgent: defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// request describes one API call. retryable marks calls that may be sent
+// more than once.
+type request struct {
+	method    string
+	path      string
+	query     url.Values
+	body      any
+	header    http.Header
+	retryable bool
+}
+
+// do sends req, retrying as the policy allows, and decodes a successful
+// JSON response into out when out is not nil.
+func (c *Client) do(ctx context.Context, req request, out any) (*http.Response, error) {
+	var body []byte
+	if req.body != nil {
+		var err error
+		if body, err = json.Marshal(req.body); err != nil {
+			return nil, fmt.Errorf("client: encode request: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, body)
+		if err == nil {
+			defer drainClose(resp.Body)
+			if out != nil {
+				if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+					return resp, fmt.Errorf("client: decode response: %w", err)
+				}
+			}
+			return resp, nil
+		}
+
+		if !req.retryable || attempt >= c.retry.MaxAttempts || !retryable(err) {
+			return nil, err
+		}
+		if err := sleep(ctx, c.retry.delay(attempt, err)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send makes a single attempt. Non-2xx responses come back as *Error with
+// the body already closed.
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), reader)
+	if err != nil {
+		return nil, fmt.Errorf("client: build request: %w", err)
+	}
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.auth != nil {
+		if err := c.auth.Authenticate(httpReq); err != nil {
+			return nil, fmt.Errorf("client: authenticate: %w", err)
+		}
+	}
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, &transportError{err: err}
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer drainClose(resp.Body)
+	return nil, newError(resp)
+}
+
+// drainClose reads what is left of body so the connection can be reused.
+func drainClose(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 64<<10))
+	_ = body.Close()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// transportError wraps failures to reach the server at all, which are worth
+// retrying.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return "client: " + e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+func retryable(err error) bool {
+	var transport *transportError
+	if errors.As(err, &transport) {
+		return true
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		case http.StatusConflict:
+			// An earlier attempt with the same Idempotency-Key is still
+			// running on the server.
+			return apiErr.RetryAfter > 0
+		}
+	}
+	return false
+}
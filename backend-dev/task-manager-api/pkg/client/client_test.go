@@ -0,0 +1,457 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/config"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/internal/server"
+	"github.com/mhShohan/go-playground/task-manager-api/task-manager/pkg/client"
+	"github.com/rs/zerolog"
+)
+
+const jwtSecret = "0123456789abcdef0123456789abcdef"
+
+// fastRetry retries like DefaultRetryPolicy without making the tests wait.
+var fastRetry = client.RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// api serves the real router on memory repositories. Every request goes
+// through intercept first, when set, which may answer it instead.
+type api struct {
+	URL       string
+	router    http.Handler
+	intercept func(w http.ResponseWriter, r *http.Request, attempt int) bool
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func newAPI(t *testing.T, env map[string]string) *api {
+	t.Helper()
+
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DATABASE_DRIVER", "memory")
+	t.Setenv("JWT_SECRET", jwtSecret)
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	srv, err := server.New(cfg, server.MemoryRepositories(), server.Options{Log: zerolog.Nop()})
+	if err != nil {
+		t.Fatalf("server.New: %v", err)
+	}
+
+	a := &api{router: srv.Router}
+	ts := httptest.NewServer(http.HandlerFunc(a.serve))
+	t.Cleanup(ts.Close)
+	a.URL = ts.URL
+	return a
+}
+
+func (a *api) serve(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	a.requests = append(a.requests, r)
+	attempt := len(a.requests)
+	intercept := a.intercept
+	a.mu.Unlock()
+
+	if intercept != nil && intercept(w, r, attempt) {
+		return
+	}
+	a.router.ServeHTTP(w, r)
+}
+
+// received returns the requests made so far and forgets them.
+func (a *api) received() []*http.Request {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	requests := a.requests
+	a.requests = nil
+	return requests
+}
+
+func (a *api) client(t *testing.T, opts ...client.Option) *client.Client {
+	t.Helper()
+	c, err := client.New(a.URL, opts...)
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+	return c
+}
+
+func token(t *testing.T, user string) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   user,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte(jwtSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// apiToken issues an API token with scopes to user.
+func apiToken(t *testing.T, a *api, user string, scopes ...string) string {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"name": "test", "scopes": scopes})
+	req, _ := http.NewRequest(http.MethodPost, a.URL+"/api/v1/tokens", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token(t, user))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("create API token: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var created struct {
+		Secret string `json:"secret"`
+	}
+	if resp.StatusCode != http.StatusCreated || json.NewDecoder(resp.Body).Decode(&created) != nil {
+		t.Fatalf("create API token: status %d", resp.StatusCode)
+	}
+	return created.Secret
+}
+
+// fail answers with an error body like the server's own.
+func fail(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":%q,"request_id":"injected"}`, http.StatusText(status))
+}
+
+func TestErrorMapping(t *testing.T) {
+	a := newAPI(t, map[string]string{"AUTH_ENFORCE_POLICY": "true"})
+	ctx := context.Background()
+	alice := a.client(t, client.WithAuth(client.BearerToken(token(t, "alice"))), client.WithRetry(client.NoRetry))
+	readOnly := a.client(t, client.WithAuth(client.APIKey(apiToken(t, a, "alice", "tasks:read"))), client.WithRetry(client.NoRetry))
+
+	tests := []struct {
+		name   string
+		call   func() error
+		want   error
+		status int
+	}{
+		{"anonymous", func() error {
+			_, err := a.client(t).ListTasks(ctx)
+			return err
+		}, client.ErrUnauthorized, http.StatusUnauthorized},
+		{"invalid token", func() error {
+			_, err := a.client(t, client.WithAuth(client.BearerToken("not-a-token"))).ListTasks(ctx)
+			return err
+		}, client.ErrUnauthorized, http.StatusUnauthorized},
+		{"missing scope", func() error {
+			_, err := readOnly.CreateTask(ctx, client.CreateTaskInput{Title: "Nope"})
+			return err
+		}, client.ErrForbidden, http.StatusForbidden},
+		{"invalid input", func() error {
+			_, err := alice.CreateTask(ctx, client.CreateTaskInput{Title: ""})
+			return err
+		}, client.ErrInvalidInput, http.StatusBadRequest},
+		{"not found", func() error {
+			_, err := alice.GetTask(ctx, uuid.New())
+			return err
+		}, client.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error %T is not a *client.Error", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, tt.status)
+			}
+			if apiErr.Message == "" || apiErr.RequestID == "" {
+				t.Errorf("Message %q and RequestID %q should come from the error body", apiErr.Message, apiErr.RequestID)
+			}
+			for _, other := range []error{client.ErrInvalidInput, client.ErrUnauthorized, client.ErrForbidden, client.ErrNotFound, client.ErrConflict, client.ErrRateLimited} {
+				if other != tt.want && errors.Is(err, other) {
+					t.Errorf("error also matches %v", other)
+				}
+			}
+		})
+	}
+
+	// API tokens work for what their scopes allow.
+	if _, err := readOnly.ListTasks(ctx); err != nil {
+		t.Errorf("ListTasks with a tasks:read token: %v", err)
+	}
+}
+
+func TestRateLimitedError(t *testing.T) {
+	a := newAPI(t, map[string]string{"RATE_LIMIT": "1", "RATE_LIMIT_BURST": "1"})
+	c := a.client(t, client.WithRetry(client.NoRetry))
+	ctx := context.Background()
+
+	if _, err := c.ListTasks(ctx); err != nil {
+		t.Fatalf("first ListTasks: %v", err)
+	}
+	_, err := c.ListTasks(ctx)
+	if !errors.Is(err, client.ErrRateLimited) {
+		t.Fatalf("second ListTasks = %v, want ErrRateLimited", err)
+	}
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter <= 0 {
+		t.Errorf("RetryAfter = %v, want it from the Retry-After header", apiErr.RetryAfter)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// failures answers the first attempts with these statuses.
+		failures []int
+		// call makes the request; the API's own answers apply after the
+		// injected failures.
+		call         func(c *client.Client, task *client.Task) error
+		wantAttempts int
+		wantStatus   int
+	}{
+		{"503 then success", []int{503, 503}, getTask, 3, 0},
+		{"429 then success", []int{429}, getTask, 2, 0},
+		{"502 and 504 then success", []int{502, 504}, getTask, 3, 0},
+		{"5xx until out of attempts", []int{500, 500, 500, 500, 500}, getTask, 4, 500},
+		{"400 is final", []int{400}, getTask, 1, 400},
+		{"403 is final", []int{403}, getTask, 1, 403},
+		{"404 from the API is final", nil, func(c *client.Client, _ *client.Task) error {
+			_, err := c.GetTask(ctx, uuid.New())
+			return err
+		}, 1, 404},
+		{"400 from the API is final", nil, func(c *client.Client, _ *client.Task) error {
+			_, err := c.CreateTask(ctx, client.CreateTaskInput{Title: ""})
+			return err
+		}, 1, 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAPI(t, nil)
+			c := a.client(t, client.WithRetry(fastRetry))
+			task, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Retry me"})
+			if err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			a.received()
+
+			a.intercept = func(w http.ResponseWriter, r *http.Request, attempt int) bool {
+				if attempt > len(tt.failures) {
+					return false
+				}
+				fail(w, tt.failures[attempt-1])
+				return true
+			}
+
+			err = tt.call(c, task)
+			if got := len(a.received()); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("call failed: %v", err)
+				}
+				return
+			}
+			var apiErr *client.Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Fatalf("error = %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func getTask(c *client.Client, task *client.Task) error {
+	got, err := c.GetTask(context.Background(), task.ID)
+	if err == nil && got.ID != task.ID {
+		return fmt.Errorf("got task %s, want %s", got.ID, task.ID)
+	}
+	return err
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	a := newAPI(t, nil)
+	a.intercept = func(w http.ResponseWriter, r *http.Request, attempt int) bool {
+		if attempt > 1 {
+			return false
+		}
+		w.Header().Set("Retry-After", "1")
+		fail(w, http.StatusServiceUnavailable)
+		return true
+	}
+
+	start := time.Now()
+	if _, err := a.client(t, client.WithRetry(fastRetry)).ListTasks(context.Background()); err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if waited := time.Since(start); waited < time.Second {
+		t.Errorf("retried after %v, want at least the 1s of Retry-After", waited)
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	a := newAPI(t, nil)
+	a.intercept = func(w http.ResponseWriter, r *http.Request, attempt int) bool {
+		w.Header().Set("Retry-After", "60")
+		fail(w, http.StatusServiceUnavailable)
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := a.client(t, client.WithRetry(fastRetry)).ListTasks(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ListTasks = %v, want context.DeadlineExceeded", err)
+	}
+	if got := len(a.received()); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+// TestCreateRetryIsIdempotent loses the response to the first attempt
+// after the API created the task; the retry must not create another.
+func TestCreateRetryIsIdempotent(t *testing.T) {
+	a := newAPI(t, nil)
+	a.intercept = func(w http.ResponseWriter, r *http.Request, attempt int) bool {
+		if attempt > 1 {
+			return false
+		}
+		a.router.ServeHTTP(httptest.NewRecorder(), r)
+		fail(w, http.StatusBadGateway)
+		return true
+	}
+
+	c := a.client(t, client.WithRetry(fastRetry))
+	ctx := context.Background()
+	created, err := c.CreateTask(ctx, client.CreateTaskInput{Title: "Only once"})
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	requests := a.received()
+	if len(requests) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(requests))
+	}
+	key := requests[0].Header.Get("Idempotency-Key")
+	if key == "" || requests[1].Header.Get("Idempotency-Key") != key {
+		t.Errorf("Idempotency-Key %q, then %q; want the same key on both", key, requests[1].Header.Get("Idempotency-Key"))
+	}
+
+	tasks, err := c.ListTasks(ctx)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != created.ID {
+		t.Errorf("ListTasks = %d tasks, want only %s", len(tasks), created.ID)
+	}
+}
+
+func TestTaskIterator(t *testing.T) {
+	a := newAPI(t, nil)
+	c := a.client(t, client.WithRetry(client.NoRetry))
+	ctx := context.Background()
+
+	const total = 7
+	for i := range total {
+		if _, err := c.CreateTask(ctx, client.CreateTaskInput{Title: fmt.Sprintf("Task %d", i)}); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+	a.received()
+
+	it := c.Tasks(ctx, 3)
+	seen := map[uuid.UUID]bool{}
+	var previous *client.Task
+	for it.Next() {
+		task := it.Task()
+		if seen[task.ID] {
+			t.Errorf("task %s returned twice", task.ID)
+		}
+		seen[task.ID] = true
+		if previous != nil && task.CreatedAt.After(previous.CreatedAt) {
+			t.Errorf("task %q comes after the older %q", task.Title, previous.Title)
+		}
+		previous = task
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if len(seen) != total {
+		t.Errorf("iterated over %d tasks, want %d", len(seen), total)
+	}
+	if it.Next() {
+		t.Error("Next after the end = true")
+	}
+
+	requests := a.received()
+	if len(requests) != 3 {
+		t.Fatalf("pages fetched = %d, want 3", len(requests))
+	}
+	for i, r := range requests {
+		if got := r.URL.Query().Get("limit"); got != "3" {
+			t.Errorf("page %d: limit = %q, want 3", i+1, got)
+		}
+		if hasCursor := r.URL.Query().Get("cursor") != ""; hasCursor != (i > 0) {
+			t.Errorf("page %d: cursor = %q", i+1, r.URL.Query().Get("cursor"))
+		}
+	}
+}
+
+func TestTaskIteratorError(t *testing.T) {
+	a := newAPI(t, nil)
+	c := a.client(t, client.WithRetry(client.NoRetry))
+	ctx := context.Background()
+
+	for i := range 4 {
+		if _, err := c.CreateTask(ctx, client.CreateTaskInput{Title: fmt.Sprintf("Task %d", i)}); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+	a.intercept = func(w http.ResponseWriter, r *http.Request, attempt int) bool {
+		if !strings.Contains(r.URL.RawQuery, "cursor=") {
+			return false
+		}
+		fail(w, http.StatusForbidden)
+		return true
+	}
+
+	it := c.Tasks(ctx, 2)
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("iterated over %d tasks before the error, want 2", n)
+	}
+	if !errors.Is(it.Err(), client.ErrForbidden) {
+		t.Errorf("Err = %v, want ErrForbidden", it.Err())
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors matched by errors.Is against an *Error, by status code.
+var (
+	ErrInvalidInput = &Error{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized = &Error{StatusCode: http.StatusUnauthorized}
+	ErrForbidden    = &Error{StatusCode: http.StatusForbidden}
+	ErrNotFound     = &Error{StatusCode: http.StatusNotFound}
+	ErrConflict     = &Error{StatusCode: http.StatusConflict}
+	ErrRateLimited  = &Error{StatusCode: http.StatusTooManyRequests}
+)
+
+// Error is a non-2xx response. Message and RequestID come from the
+// server's error body; quote RequestID when reporting a problem, it ties
+// the call to the server's logs.
+type Error struct {
+	StatusCode int
+	Message    string
+	RequestID  string
+	// RetryAfter is set from the Retry-After header of 409, 429 and 503
+	// responses.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("task manager API: %d %s (request %s)", e.StatusCode, msg, e.RequestID)
+	}
+	return fmt.Sprintf("task manager API: %d %s", e.StatusCode, msg)
+}
+
+// Is makes errors.Is(err, ErrNotFound) and friends work. 422 counts as
+// invalid input too.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t == ErrInvalidInput && e.StatusCode == http.StatusUnprocessableEntity {
+		return true
+	}
+	return t.Message == "" && t.StatusCode == e.StatusCode
+}
+
+func newError(resp *http.Response) *Error {
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	var body struct {
+		Error     string `json:"error"`
+		RequestID string `json:"request_id"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		e.Message = body.Error
+		if body.RequestID != "" {
+			e.RequestID = body.RequestID
+		}
+	}
+
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	}
+
+	return e
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how calls that are safe to repeat are retried.
+// Delays grow exponentially from BaseDelay up to MaxDelay with full jitter,
+// unless the server asks for a longer wait with Retry-After.
+type RetryPolicy struct {
+	// MaxAttempts counts the first try; 1 disables retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var (
+	DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}
+	NoRetry            = RetryPolicy{MaxAttempts: 1}
+)
+
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	backoff := p.MaxDelay
+	if shift := attempt - 1; shift < 30 && p.BaseDelay<<shift < p.MaxDelay {
+		backoff = p.BaseDelay << shift
+	}
+	var d time.Duration
+	if backoff > 0 {
+		d = rand.N(backoff)
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		d = apiErr.RetryAfter
+	}
+	return d
+}
@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskStatus string
+
+const (
+	TaskStatusTodo       TaskStatus = "TODO"
+	TaskStatusInProgress TaskStatus = "IN_PROGRESS"
+	TaskStatusDone       TaskStatus = "DONE"
+)
+
+// TaskPriority runs from P0, the most urgent, to P3.
+type TaskPriority string
+
+const (
+	TaskPriorityP0 TaskPriority = "P0"
+	TaskPriorityP1 TaskPriority = "P1"
+	TaskPriorityP2 TaskPriority = "P2"
+	TaskPriorityP3 TaskPriority = "P3"
+)
+
+type Task struct {
+	ID          uuid.UUID    `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	Rank        string       `json:"rank"`
+	Assignees   []string     `json:"assignees,omitempty"`
+	Watchers    []string     `json:"watchers,omitempty"`
+	ProjectID   *uuid.UUID   `json:"project_id,omitempty"`
+	DueDate     *time.Time   `json:"due_date,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+type CreateTaskInput struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	Status      TaskStatus   `json:"status,omitempty"`
+	Priority    TaskPriority `json:"priority,omitempty"`
+	ProjectID   *uuid.UUID   `json:"project_id,omitempty"`
+	DueDate     *time.Time   `json:"due_date,omitempty"`
+}
+
+// UpdateTaskInput changes only the fields that are set.
+type UpdateTaskInput struct {
+	Title       *string       `json:"title,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Status      *TaskStatus   `json:"status,omitempty"`
+	Priority    *TaskPriority `json:"priority,omitempty"`
+	DueDate     *time.Time    `json:"due_date,omitempty"`
+}
+
+// MoveTaskInput places a task right after After, right before Before, or
+// between the two.
+type MoveTaskInput struct {
+	Before *uuid.UUID `json:"before,omitempty"`
+	After  *uuid.UUID `json:"after,omitempty"`
+}
+
+const (
+	tasksPath       = "/api/v1/tasks"
+	defaultPageSize = 50
+)
+
+// CreateTask creates a task. Every call carries a fresh Idempotency-Key, so
+// retries never create it twice.
+func (c *Client) CreateTask(ctx context.Context, input CreateTaskInput) (*Task, error) {
+	var task Task
+	_, err := c.do(ctx, request{
+		method:    http.MethodPost,
+		path:      tasksPath,
+		body:      input,
+		header:    http.Header{"Idempotency-Key": {uuid.NewString()}},
+		retryable: true,
+	}, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, id uuid.UUID) (*Task, error) {
+	var task Task
+	_, err := c.do(ctx, request{
+		method:    http.MethodGet,
+		path:      taskPath(id),
+		retryable: true,
+	}, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListTasks returns every task in one call. Use Tasks to page through a
+// large list instead.
+func (c *Client) ListTasks(ctx context.Context) ([]*Task, error) {
+	var tasks []*Task
+	_, err := c.do(ctx, request{
+		method:    http.MethodGet,
+		path:      tasksPath,
+		retryable: true,
+	}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (c *Client) UpdateTask(ctx context.Context, id uuid.UUID, input UpdateTaskInput) (*Task, error) {
+	var task Task
+	_, err := c.do(ctx, request{
+		method:    http.MethodPut,
+		path:      taskPath(id),
+		body:      input,
+		retryable: true,
+	}, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// MoveTask changes where the task sits in the manual ordering.
+func (c *Client) MoveTask(ctx context.Context, id uuid.UUID, input MoveTaskInput) (*Task, error) {
+	var task Task
+	_, err := c.do(ctx, request{
+		method:    http.MethodPost,
+		path:      taskPath(id) + "/move",
+		body:      input,
+		retryable: true,
+	}, &task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// AssignTask adds user, or "me" for the authenticated caller, to the
+// task's assignees.
+func (c *Client) AssignTask(ctx context.Context, id uuid.UUID, user string) (*Task, error) {
+	return c.changeTaskUser(ctx, http.MethodPost, taskPath(id)+"/assignees", user)
+}
+
+// UnassignTask removes user, or "me", from the task's assignees.
+func (c *Client) UnassignTask(ctx context.Context, id uuid.UUID, user string) (*Task, error) {
+	return c.changeTaskUser(ctx, http.MethodDelete, taskPath(id)+"/assignees/"+user, "")
+}
+
+// WatchTask adds user, or "me" for the authenticated caller, to the task's
+// watchers.
+func (c *Client) WatchTask(ctx context.Context, id uuid.UUID, user string) (*Task, error) {
+	return c.changeTaskUser(ctx, http.MethodPost, taskPath(id)+"/watchers", user)
+}
+
+// UnwatchTask removes user, or "me", from the task's watchers.
+func (c *Client) UnwatchTask(ctx context.Context, id uuid.UUID, user string) (*Task, error) {
+	return c.changeTaskUser(ctx, http.MethodDelete, taskPath(id)+"/watchers/"+user, "")
+}
+
+// changeTaskUser sends user in the body when it is set. Adding and
+// removing users is idempotent, so both are retried.
+func (c *Client) changeTaskUser(ctx context.Context, method, path, user string) (*Task, error) {
+	req := request{method: method, path: path, retryable: true}
+	if user != "" {
+		req.body = map[string]string{"user": user}
+	}
+
+	var task Task
+	if _, err := c.do(ctx, req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask deletes a task. A retry after a lost response may report
+// ErrNotFound for a task this call did delete.
+func (c *Client) DeleteTask(ctx context.Context, id uuid.UUID) error {
+	_, err := c.do(ctx, request{
+		method:    http.MethodDelete,
+		path:      taskPath(id),
+		retryable: true,
+	}, nil)
+	return err
+}
+
+func taskPath(id uuid.UUID) string {
+	return tasksPath + "/" + id.String()
+}
+
+// TaskIterator pages through tasks, newest first, fetching the next page
+// when the current one runs out:
+//
+//	it := c.Tasks(ctx, 100)
+//	for it.Next() {
+//		task := it.Task()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type TaskIterator struct {
+	ctx      context.Context
+	client   *Client
+	pageSize int
+
+	page    []*Task
+	current *Task
+	cursor  string
+	done    bool
+	err     error
+}
+
+// Tasks returns an iterator over all tasks, fetched pageSize at a time, up
+// to 100. A pageSize of 0 means 50.
+func (c *Client) Tasks(ctx context.Context, pageSize int) *TaskIterator {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return &TaskIterator{ctx: ctx, client: c, pageSize: pageSize}
+}
+
+// Next advances to the next task, fetching a page if needed. It returns
+// false at the end or on error; check Err to tell them apart.
+func (it *TaskIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.err = it.fetch()
+	}
+
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Task is the task Next advanced to.
+func (it *TaskIterator) Task() *Task {
+	return it.current
+}
+
+func (it *TaskIterator) Err() error {
+	return it.err
+}
+
+func (it *TaskIterator) fetch() error {
+	query := url.Values{"limit": {strconv.Itoa(it.pageSize)}}
+	if it.cursor != "" {
+		query.Set("cursor", it.cursor)
+	}
+
+	var page []*Task
+	resp, err := it.client.do(it.ctx, request{
+		method:    http.MethodGet,
+		path:      tasksPath,
+		query:     query,
+		retryable: true,
+	}, &page)
+	if err != nil {
+		return err
+	}
+
+	it.page = page
+	it.cursor = nextCursor(resp.Header.Get("Link"))
+	it.done = it.cursor == ""
+	return nil
+}
+
+// nextCursor extracts the cursor of the rel="next" link from a Link header.
+func nextCursor(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			return ""
+		}
+		return u.Query().Get("cursor")
+	}
+	return ""
+}
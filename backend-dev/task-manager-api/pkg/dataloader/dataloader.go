@@ -0,0 +1,121 @@
+// Package dataloader coalesces lookups by key into batched fetches. Loads
+// issued within a short window are collected and resolved with a single
+// call, and every key is fetched at most once per Loader, so a Loader is
+// meant to live for one request.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc fetches the values for keys. Keys missing from the returned map
+// load as the zero value; an error fails every key of the batch.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+}
+
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+// New returns a Loader that waits up to wait for more keys before fetching,
+// and fetches right away once maxBatch keys are pending.
+func New[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value for key, batching the fetch with concurrent loads.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	r, ok := l.cache[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.cache[key] = r
+		l.enqueue(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// Prime stores a value fetched some other way, such as by a list query, so
+// later loads of key don't hit the backend. Keys already loaded are kept.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.cache[key]; ok {
+		return
+	}
+	r := &result[V]{done: make(chan struct{}), value: value}
+	close(r.done)
+	l.cache[key] = r
+}
+
+// enqueue adds key to the pending batch, starting one if needed. l.mu must
+// be held.
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	if l.pending == nil {
+		b := &batch[K, V]{}
+		l.pending = b
+		time.AfterFunc(l.wait, func() {
+			l.mu.Lock()
+			if l.pending != b {
+				// Already dispatched because it filled up.
+				l.mu.Unlock()
+				return
+			}
+			l.pending = nil
+			l.mu.Unlock()
+			l.dispatch(ctx, b)
+		})
+	}
+
+	b := l.pending
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+
+	if len(b.keys) >= l.maxBatch {
+		l.pending = nil
+		go l.dispatch(ctx, b)
+	}
+}
+
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	// The batch outlives the load that started it; other loads in it may
+	// still be waiting after that one gave up.
+	values, err := l.fetch(context.WithoutCancel(ctx), b.keys)
+	for i, key := range b.keys {
+		r := b.results[i]
+		r.value, r.err = values[key], err
+		close(r.done)
+	}
+}
@@ -0,0 +1,74 @@
+// Package ical writes RFC 5545 iCalendar documents.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding.
+const maxLineOctets = 75
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// Writer accumulates content lines, folding and terminating them with CRLF
+// as the format requires.
+type Writer struct {
+	buf bytes.Buffer
+}
+
+func (w *Writer) Begin(component string) {
+	w.Prop("BEGIN", component)
+}
+
+func (w *Writer) End(component string) {
+	w.Prop("END", component)
+}
+
+// Prop writes a property whose value is already in iCalendar form.
+func (w *Writer) Prop(name, value string) {
+	w.line(name + ":" + value)
+}
+
+// Text writes a TEXT property, escaping the value.
+func (w *Writer) Text(name, value string) {
+	w.Prop(name, textEscaper.Replace(value))
+}
+
+// Time writes a DATE-TIME property in UTC.
+func (w *Writer) Time(name string, t time.Time) {
+	w.Prop(name, FormatTime(t))
+}
+
+func (w *Writer) Bytes() []byte {
+	return w.buf.Bytes()
+}
+
+func (w *Writer) line(s string) {
+	// Continuation lines start with a space, which counts towards the limit.
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
+
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,373 @@
+// Package jsonpatch applies JSON Merge Patch (RFC 7396) and JSON Patch
+// (RFC 6902) documents to JSON values.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch is returned for malformed patch documents.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPathNotFound is returned when an operation targets a location that
+	// does not exist.
+	ErrPathNotFound = errors.New("path not found")
+	// ErrTestFailed is returned when a "test" operation does not match.
+	ErrTestFailed = errors.New("test operation failed")
+)
+
+// MergePatch applies an RFC 7396 merge patch to doc.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	var target, p any
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	return json.Marshal(mergeValue(target, p))
+}
+
+func mergeValue(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = map[string]any{}
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergeValue(targetObj[key], value)
+	}
+
+	return targetObj
+}
+
+// Operation is a single RFC 6902 operation. Value holds the operation's
+// value as raw JSON and is nil when the member is absent; a JSON null is a
+// value like any other and is kept as "null".
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// UnmarshalJSON decodes an operation, telling a "value" of null apart from
+// a missing one.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if members == nil {
+		return errors.New("operation must be an object")
+	}
+
+	*o = Operation{}
+	for name, dst := range map[string]*string{"op": &o.Op, "path": &o.Path, "from": &o.From} {
+		raw, ok := members[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, dst); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	if raw, ok := members["value"]; ok {
+		o.Value = raw
+	}
+	return nil
+}
+
+// Apply applies an RFC 6902 patch to doc. Operations are applied in order
+// and the patch fails as a whole if any of them fails.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var root any
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		var err error
+		root, err = applyOperation(root, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(root)
+}
+
+func applyOperation(root any, op Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	value := func() (any, error) {
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		var v any
+		if err := json.Unmarshal(op.Value, &v); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return v, nil
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, v)
+
+	case "remove":
+		root, _, err := remove(root, path)
+		return root, err
+
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		root, _, err = remove(root, path)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, v)
+
+	case "move":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("%w: cannot move a value into one of its children", ErrInvalidPatch)
+		}
+		root, v, err := remove(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, v)
+
+	case "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, deepCopy(v))
+
+	case "test":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		current, err := get(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, v) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", ErrInvalidPatch, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func get(root any, path []string) (any, error) {
+	current := root
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]any:
+			v, ok := node[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			current = v
+		case []any:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return current, nil
+}
+
+func add(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return root, nil
+	case []any:
+		i := len(node)
+		if last != "-" {
+			if i, err = arrayIndex(last, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		grown := append(node[:i:i], append([]any{value}, node[i:]...)...)
+		return replaceChild(root, path[:len(path)-1], grown)
+	}
+
+	return nil, ErrPathNotFound
+}
+
+func remove(root any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, root, nil
+	}
+
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		v, ok := node[last]
+		if !ok {
+			return nil, nil, ErrPathNotFound
+		}
+		delete(node, last)
+		return root, v, nil
+	case []any:
+		i, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		v := node[i]
+		shrunk := append(node[:i:i], node[i+1:]...)
+		root, err = replaceChild(root, path[:len(path)-1], shrunk)
+		return root, v, err
+	}
+
+	return nil, nil, ErrPathNotFound
+}
+
+// replaceChild stores value at path. Arrays change length on add and
+// remove, so the new slice has to be written back into its parent.
+func replaceChild(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		i, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+	}
+
+	return root, nil
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, token)
+	}
+	if i > max {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
+
+func deepCopy(v any) any {
+	switch node := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(node))
+		for k, child := range node {
+			c[k] = deepCopy(child)
+		}
+		return c
+	case []any:
+		c := make([]any, len(node))
+		for i, child := range node {
+			c[i] = deepCopy(child)
+		}
+		return c
+	}
+	return v
+}
@@ -0,0 +1,293 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestApply runs the examples from RFC 6902, Appendix A, followed by the
+// edge cases the RFC leaves to the pointer rules of RFC 6901.
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			"A.1 adding an object member",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			`{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			"A.2 adding an array element",
+			`{"foo": ["bar", "baz"]}`,
+			`[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			`{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			"A.3 removing an object member",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "remove", "path": "/baz"}]`,
+			`{"foo": "bar"}`,
+		},
+		{
+			"A.4 removing an array element",
+			`{"foo": ["bar", "qux", "baz"]}`,
+			`[{"op": "remove", "path": "/foo/1"}]`,
+			`{"foo": ["bar", "baz"]}`,
+		},
+		{
+			"A.5 replacing a value",
+			`{"baz": "qux", "foo": "bar"}`,
+			`[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			`{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			"A.6 moving a value",
+			`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			`[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			`{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			"A.7 moving an array element",
+			`{"foo": ["all", "grass", "cows", "eat"]}`,
+			`[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			`{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			"A.8 testing a value: success",
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			`[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			`{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			"A.10 adding a nested member object",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			`{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			"A.11 ignoring unrecognized elements",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			`{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			"A.14 ~ escape ordering",
+			`{"/": 9, "~1": 10}`,
+			`[{"op": "test", "path": "/~01", "value": 10}]`,
+			`{"/": 9, "~1": 10}`,
+		},
+		{
+			"A.16 adding an array value",
+			`{"foo": ["bar"]}`,
+			`[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			`{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		{
+			"~1 escapes a slash",
+			`{"a/b": 1}`,
+			`[{"op": "replace", "path": "/a~1b", "value": 2}]`,
+			`{"a/b": 2}`,
+		},
+		{
+			"~0 escapes a tilde",
+			`{"m~n": 1}`,
+			`[{"op": "copy", "from": "/m~0n", "path": "/m~0~1n"}]`,
+			`{"m~n": 1, "m~/n": 1}`,
+		},
+		{
+			"- appends to an array",
+			`{"foo": []}`,
+			`[{"op": "add", "path": "/foo/-", "value": 1}, {"op": "add", "path": "/foo/-", "value": 2}]`,
+			`{"foo": [1, 2]}`,
+		},
+		{
+			"null is a value",
+			`{"foo": "bar"}`,
+			`[{"op": "replace", "path": "/foo", "value": null}]`,
+			`{"foo": null}`,
+		},
+		{
+			"replace the whole document",
+			`{"foo": "bar"}`,
+			`[{"op": "replace", "path": "", "value": [1]}]`,
+			`[1]`,
+		},
+		{
+			"move onto itself",
+			`{"foo": {"bar": 1}}`,
+			`[{"op": "move", "from": "/foo", "path": "/foo"}]`,
+			`{"foo": {"bar": 1}}`,
+		},
+		{
+			"copy is deep",
+			`{"a": {"b": 1}}`,
+			`[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "replace", "path": "/c/b", "value": 2}]`,
+			`{"a": {"b": 1}, "c": {"b": 2}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  error
+	}{
+		{
+			"A.9 testing a value: error",
+			`{"baz": "qux"}`,
+			`[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			ErrTestFailed,
+		},
+		{
+			"A.12 adding to a nonexistent target",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			ErrPathNotFound,
+		},
+		{
+			"A.13 invalid JSON patch document",
+			`{"foo": "bar"}`,
+			`[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+			ErrPathNotFound,
+		},
+		{
+			"A.15 comparing strings and numbers",
+			`{"/": 9, "~1": 10}`,
+			`[{"op": "test", "path": "/~01", "value": "10"}]`,
+			ErrTestFailed,
+		},
+		{
+			"move into its own child",
+			`{"foo": {"bar": 1}}`,
+			`[{"op": "move", "from": "/foo", "path": "/foo/bar/baz"}]`,
+			ErrInvalidPatch,
+		},
+		{
+			"- is not an element",
+			`{"foo": [1]}`,
+			`[{"op": "remove", "path": "/foo/-"}]`,
+			ErrInvalidPatch,
+		},
+		{
+			"index past the end",
+			`{"foo": [1]}`,
+			`[{"op": "add", "path": "/foo/2", "value": 2}]`,
+			ErrPathNotFound,
+		},
+		{
+			"index with a leading zero",
+			`{"foo": [1, 2]}`,
+			`[{"op": "remove", "path": "/foo/01"}]`,
+			ErrInvalidPatch,
+		},
+		{
+			"pointer without a slash",
+			`{"foo": 1}`,
+			`[{"op": "remove", "path": "foo"}]`,
+			ErrInvalidPatch,
+		},
+		{
+			"missing value",
+			`{"foo": 1}`,
+			`[{"op": "add", "path": "/bar"}]`,
+			ErrInvalidPatch,
+		},
+		{
+			"unknown op",
+			`{"foo": 1}`,
+			`[{"op": "frobnicate", "path": "/foo"}]`,
+			ErrInvalidPatch,
+		},
+		{
+			"patch is not an array",
+			`{"foo": 1}`,
+			`{"op": "remove", "path": "/foo"}`,
+			ErrInvalidPatch,
+		},
+		{
+			"operations see earlier ones",
+			`{"foo": 1}`,
+			`[{"op": "remove", "path": "/foo"}, {"op": "test", "path": "/foo", "value": 1}]`,
+			ErrPathNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Apply = %s, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestMergePatch runs the examples from RFC 7396, Appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.doc+" "+tt.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergePatchInvalid(t *testing.T) {
+	if _, err := MergePatch([]byte(`{}`), []byte(`{"a":`)); !errors.Is(err, ErrInvalidPatch) {
+		t.Errorf("MergePatch = %v, want ErrInvalidPatch", err)
+	}
+}
+
+func assertJSON(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("want %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// backupTimeFormat sorts chronologically and is safe in file names.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	// rotateRetryInterval is how long a file that failed to rotate is
+	// written to as it is before rotating it is tried again.
+	rotateRetryInterval = time.Minute
+)
+
+// FileOptions configures a RotatingFile. Zero values disable the
+// corresponding limit.
+type FileOptions struct {
+	Path string
+	// MaxSize is the size in bytes after which the file is rotated.
+	MaxSize int64
+	// RotateEvery rotates the file on the first write after this long.
+	RotateEvery time.Duration
+	// MaxAge removes rotated files older than this.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept.
+	MaxBackups int
+}
+
+// RotatingFile is an io.WriteCloser appending to a log file that is rotated
+// by size and age. Rotated files get a timestamp before the extension, so
+// api.log becomes api-2006-01-02T15-04-05.000.log. When rotating fails, the
+// error is reported on stderr and writes go on to the current file until
+// the next attempt.
+type RotatingFile struct {
+	opts FileOptions
+	now  func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	retryAt  time.Time
+}
+
+func OpenRotatingFile(opts FileOptions) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+		return nil, err
+	}
+
+	f := &RotatingFile{opts: opts, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	now := f.now()
+	tooBig := f.opts.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.opts.MaxSize
+	tooOld := f.opts.RotateEvery > 0 && now.Sub(f.openedAt) >= f.opts.RotateEvery
+	if (tooBig || tooOld) && !now.Before(f.retryAt) {
+		if err := f.rotate(now); err != nil {
+			// Losing the logs would be worse than a file over its limits.
+			f.retryAt = now.Add(rotateRetryInterval)
+			fmt.Fprintf(os.Stderr, "logger: cannot rotate %s, retrying in %s: %v\n", f.opts.Path, rotateRetryInterval, err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+// rotate renames the file to a backup and opens a new one. The current file
+// is only closed once the new one is open, so on failure writes can go on
+// to it, under its old name or the backup's.
+func (f *RotatingFile) rotate(now time.Time) error {
+	prefix, ext := f.backupPattern()
+	backup := prefix + now.Format(backupTimeFormat) + ext
+	// The file is gone when it was removed from under us, or renamed by an
+	// attempt that failed to open the new one; then only the open is left.
+	if err := os.Rename(f.opts.Path, backup); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	old := f.file
+	if err := f.open(); err != nil {
+		return err
+	}
+	_ = old.Close()
+
+	f.prune()
+	return nil
+}
+
+// prune removes rotated files beyond MaxBackups or older than MaxAge. It is
+// best effort; a file that cannot be removed is retried on the next
+// rotation.
+func (f *RotatingFile) prune() {
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+
+	prefix, ext := f.backupPattern()
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return
+	}
+
+	var backups []string
+	for _, m := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(m, prefix), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, m)
+		}
+	}
+
+	// Newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	for i, backup := range backups {
+		expired := f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups
+		if !expired && f.opts.MaxAge > 0 {
+			if info, err := os.Stat(backup); err == nil && f.now().Sub(info.ModTime()) > f.opts.MaxAge {
+				expired = true
+			}
+		}
+		if expired {
+			_ = os.Remove(backup)
+		}
+	}
+}
+
+func (f *RotatingFile) backupPattern() (prefix, ext string) {
+	ext = filepath.Ext(f.opts.Path)
+	return fmt.Sprintf("%s-", strings.TrimSuffix(f.opts.Path, ext)), ext
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeClock is a settable clock for RotatingFile.now.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func openTestFile(t *testing.T, opts FileOptions) (*RotatingFile, *fakeClock) {
+	t.Helper()
+	opts.Path = filepath.Join(t.TempDir(), "logs", "api.log")
+
+	f, err := OpenRotatingFile(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	// Start at the real time, which the files' modification times are in.
+	clock := &fakeClock{now: time.Now()}
+	f.now = clock.Now
+	f.openedAt = clock.now
+	return f, clock
+}
+
+func mustWrite(t *testing.T, f *RotatingFile, line string) {
+	t.Helper()
+	if n, err := f.Write([]byte(line)); err != nil || n != len(line) {
+		t.Fatalf("Write(%q) = %d, %v", line, n, err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// backups returns the rotated files next to f, oldest first.
+func backups(t *testing.T, f *RotatingFile) []string {
+	t.Helper()
+	prefix, ext := f.backupPattern()
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(matches)
+	return matches
+}
+
+func backupPath(f *RotatingFile, at time.Time) string {
+	prefix, ext := f.backupPattern()
+	return prefix + at.Format(backupTimeFormat) + ext
+}
+
+func TestRotatingFileRotatesBySize(t *testing.T) {
+	f, clock := openTestFile(t, FileOptions{MaxSize: 10})
+
+	mustWrite(t, f, "first\n")
+	mustWrite(t, f, "2nd\n")
+	clock.Advance(time.Second)
+	mustWrite(t, f, "third\n")
+
+	if got := backups(t, f); !slices.Equal(got, []string{backupPath(f, clock.now)}) {
+		t.Fatalf("backups = %v", got)
+	}
+	if got := readFile(t, backupPath(f, clock.now)); got != "first\n2nd\n" {
+		t.Errorf("backup = %q", got)
+	}
+	if got := readFile(t, f.opts.Path); got != "third\n" {
+		t.Errorf("file = %q", got)
+	}
+}
+
+func TestRotatingFileRotatesByAge(t *testing.T) {
+	f, clock := openTestFile(t, FileOptions{RotateEvery: time.Hour})
+
+	mustWrite(t, f, "first\n")
+	clock.Advance(59 * time.Minute)
+	mustWrite(t, f, "second\n")
+	if got := backups(t, f); len(got) != 0 {
+		t.Fatalf("rotated early: %v", got)
+	}
+
+	clock.Advance(time.Minute)
+	mustWrite(t, f, "third\n")
+	if got := readFile(t, backupPath(f, clock.now)); got != "first\nsecond\n" {
+		t.Errorf("backup = %q", got)
+	}
+	if got := readFile(t, f.opts.Path); got != "third\n" {
+		t.Errorf("file = %q", got)
+	}
+}
+
+func TestRotatingFilePrunes(t *testing.T) {
+	t.Run("max backups", func(t *testing.T) {
+		f, clock := openTestFile(t, FileOptions{MaxSize: 1, MaxBackups: 2})
+
+		var rotated []string
+		for _, line := range []string{"a\n", "b\n", "c\n", "d\n", "e\n"} {
+			clock.Advance(time.Second)
+			mustWrite(t, f, line)
+			rotated = append(rotated, backupPath(f, clock.now))
+		}
+
+		// The first write rotates nothing, the others one file each.
+		if got, want := backups(t, f), rotated[3:]; !slices.Equal(got, want) {
+			t.Errorf("backups = %v, want %v", got, want)
+		}
+		if got := readFile(t, rotated[4]); got != "d\n" {
+			t.Errorf("newest backup = %q", got)
+		}
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		f, clock := openTestFile(t, FileOptions{MaxSize: 1, MaxAge: 24 * time.Hour})
+
+		old := backupPath(f, clock.now.Add(-48*time.Hour))
+		recent := backupPath(f, clock.now.Add(-time.Hour))
+		for path, age := range map[string]time.Duration{old: 48 * time.Hour, recent: time.Hour} {
+			if err := os.WriteFile(path, []byte("old\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			mtime := clock.now.Add(-age)
+			if err := os.Chtimes(path, mtime, mtime); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		mustWrite(t, f, "a\n")
+		mustWrite(t, f, "b\n")
+
+		if got, want := backups(t, f), []string{recent, backupPath(f, clock.now)}; !slices.Equal(got, want) {
+			t.Errorf("backups = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestRotatingFileKeepsWritingWhenRotationFails(t *testing.T) {
+	f, clock := openTestFile(t, FileOptions{MaxSize: 1})
+
+	// A non-empty directory where the backup should go makes the rename
+	// fail.
+	mustWrite(t, f, "a\n")
+	blocked := backupPath(f, clock.now)
+	if err := os.MkdirAll(filepath.Join(blocked, "taken"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	mustWrite(t, f, "b\n")
+	if got := readFile(t, f.opts.Path); got != "a\nb\n" {
+		t.Fatalf("file = %q", got)
+	}
+
+	// Until the retry is due, writes don't try again.
+	if err := os.RemoveAll(blocked); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(rotateRetryInterval - time.Second)
+	mustWrite(t, f, "c\n")
+	if got := backups(t, f); len(got) != 0 {
+		t.Fatalf("retried early: %v", got)
+	}
+
+	clock.Advance(time.Second)
+	mustWrite(t, f, "d\n")
+	if got := readFile(t, backupPath(f, clock.now)); got != "a\nb\nc\n" {
+		t.Errorf("backup = %q", got)
+	}
+	if got := readFile(t, f.opts.Path); got != "d\n" {
+		t.Errorf("file = %q", got)
+	}
+}
+
+func TestRotatingFileWriteAfterClose(t *testing.T) {
+	f, _ := openTestFile(t, FileOptions{})
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Errorf("Write after Close = %v, want os.ErrClosed", err)
+	}
+}
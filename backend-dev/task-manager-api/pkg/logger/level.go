@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler serves the global log level: GET returns it and PUT with a
+// body such as {"level":"debug"} changes it until the next restart or
+// configuration reload. Serve it on the admin port only.
+func LevelHandler(log zerolog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+				return
+			}
+
+			level, err := zerolog.ParseLevel(body.Level)
+			if err != nil || body.Level == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid log level"})
+				return
+			}
+
+			log.Warn().Stringer("from", zerolog.GlobalLevel()).Stringer("to", level).Msg("Log level changed")
+			zerolog.SetGlobalLevel(level)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "Method not allowed"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, levelBody{Level: zerolog.GlobalLevel().String()})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
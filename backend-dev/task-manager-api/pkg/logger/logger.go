@@ -1,22 +1,79 @@
 package logger
 
 import (
+	"io"
 	"os"
 	"time"
 
 	"github.com/rs/zerolog"
 )
 
-func New() zerolog.Logger {
-	output := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: time.RFC3339,
+// Options describes the logger built by New.
+type Options struct {
+	// Format is "json" or "pretty" (human readable console output).
+	Format string
+
+	// SampleBurst, when non-zero, keeps only the first SampleBurst debug
+	// and info messages in every SamplePeriod. Warnings and errors are
+	// never sampled.
+	SampleBurst  uint32
+	SamplePeriod time.Duration
+
+	// File adds a JSON log file next to stdout. Nil disables it.
+	File *FileOptions
+}
+
+// Default returns a console logger for use before the configuration has
+// been loaded.
+func Default() zerolog.Logger {
+	return New(Options{Format: "pretty"}, nil)
+}
+
+// Open builds the logger described by opts, opening the log file if one is
+// configured. The returned closer is nil when there is no file.
+func Open(opts Options) (zerolog.Logger, io.Closer, error) {
+	if opts.File == nil {
+		return New(opts, nil), nil, nil
+	}
+
+	file, err := OpenRotatingFile(*opts.File)
+	if err != nil {
+		return zerolog.Logger{}, nil, err
 	}
 
-	return zerolog.New(output).
+	return New(opts, file), file, nil
+}
+
+// New builds a logger writing to stdout in opts.Format and, when file is
+// not nil, to file as JSON. The level is left to zerolog.SetGlobalLevel so
+// it can be changed at runtime for every logger at once.
+func New(opts Options, file io.Writer) zerolog.Logger {
+	var output io.Writer = os.Stdout
+	if opts.Format != "json" {
+		output = zerolog.ConsoleWriter{
+			Out:        os.Stdout,
+			TimeFormat: time.RFC3339,
+		}
+	}
+
+	if file != nil {
+		output = zerolog.MultiLevelWriter(output, file)
+	}
+
+	log := zerolog.New(output).
 		With().
 		Timestamp().
 		Caller().
-		Logger().
-		Level(zerolog.InfoLevel)
+		Logger()
+
+	if opts.SampleBurst > 0 {
+		sampler := &zerolog.BurstSampler{Burst: opts.SampleBurst, Period: opts.SamplePeriod}
+		log = log.Sample(&zerolog.LevelSampler{
+			TraceSampler: sampler,
+			DebugSampler: sampler,
+			InfoSampler:  sampler,
+		})
+	}
+
+	return log
 }
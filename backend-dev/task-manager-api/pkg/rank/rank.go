@@ -0,0 +1,267 @@
+// Package rank generates fractional-index keys: strings that sort in byte
+// order and between any two of which another key can be generated, so an
+// item can be moved by changing its key alone.
+//
+// A key is an integer part followed by a fraction, both in base 62. The
+// first character of the integer part encodes its length, so appending at
+// either end grows keys logarithmically; inserting repeatedly into the
+// same gap lengthens the fraction by about one character every six
+// insertions.
+package rank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// digits are the base-62 digits in byte order.
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// smallestInteger is the lowest integer part. Keys can't go below it, so it
+// is never a key by itself.
+var smallestInteger = "A" + strings.Repeat("0", 26)
+
+var (
+	// ErrInvalidKey is returned for strings that are not keys.
+	ErrInvalidKey = errors.New("invalid rank key")
+	// ErrNoRoom is returned when the lower bound is not below the upper
+	// bound, so no key fits between them.
+	ErrNoRoom = errors.New("no rank key fits between the bounds")
+)
+
+// Between returns a key that sorts after a and before b. An empty a means
+// before every key, an empty b after every key.
+func Between(a, b string) (string, error) {
+	if a != "" {
+		if err := Validate(a); err != nil {
+			return "", err
+		}
+	}
+	if b != "" {
+		if err := Validate(b); err != nil {
+			return "", err
+		}
+	}
+	if a != "" && b != "" && a >= b {
+		return "", fmt.Errorf("%w: %q is not below %q", ErrNoRoom, a, b)
+	}
+
+	if a == "" {
+		if b == "" {
+			return "a" + digits[:1], nil
+		}
+		ib := integerPart(b)
+		fb := b[len(ib):]
+		if ib == smallestInteger {
+			return ib + midpoint("", fb), nil
+		}
+		if ib < b {
+			return ib, nil
+		}
+		res, ok := decrementInteger(ib)
+		if !ok {
+			return "", fmt.Errorf("%w: nothing sorts before %q", ErrNoRoom, b)
+		}
+		return res, nil
+	}
+
+	ia := integerPart(a)
+	fa := a[len(ia):]
+	if b == "" {
+		if i, ok := incrementInteger(ia); ok {
+			return i, nil
+		}
+		return ia + midpoint(fa, ""), nil
+	}
+
+	ib := integerPart(b)
+	fb := b[len(ib):]
+	if ia == ib {
+		return ia + midpoint(fa, fb), nil
+	}
+	i, ok := incrementInteger(ia)
+	if ok && i < b {
+		return i, nil
+	}
+	return ia + midpoint(fa, ""), nil
+}
+
+// N returns n keys in ascending order between a and b, as evenly spread as
+// Between allows, so they stay short.
+func N(a, b string, n int) ([]string, error) {
+	keys := make([]string, 0, n)
+	var fill func(a, b string, n int) error
+	fill = func(a, b string, n int) error {
+		switch {
+		case n == 0:
+			return nil
+		case b == "":
+			// Appending only lengthens the integer part now and then.
+			for ; n > 0; n-- {
+				key, err := Between(a, b)
+				if err != nil {
+					return err
+				}
+				keys = append(keys, key)
+				a = key
+			}
+			return nil
+		}
+
+		key, err := Between(a, b)
+		if err != nil {
+			return err
+		}
+		if err := fill(a, key, n/2); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		return fill(key, b, n-n/2-1)
+	}
+
+	if err := fill(a, b, n); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Validate reports whether key is a well-formed key.
+func Validate(key string) error {
+	if key == "" || key == smallestInteger {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	n, ok := integerLength(key[0])
+	if !ok || n > len(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for i := 1; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	// A trailing zero would make the fraction equal to a shorter one.
+	if len(key) > n && key[len(key)-1] == digits[0] {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// integerLength is the length of the integer part starting with head: a-z
+// start positive integers of 2 to 27 characters, Z-A negative ones.
+func integerLength(head byte) (int, bool) {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2, true
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2, true
+	}
+	return 0, false
+}
+
+func integerPart(key string) string {
+	n, _ := integerLength(key[0])
+	return key[:n]
+}
+
+// midpoint returns a fraction between fractions a and b, where an empty b
+// means 1.
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the common prefix, reading missing digits of a as zero.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(tail(a, n), b[n:])
+		}
+	}
+
+	// The first digits differ.
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(digits, a[0])
+	}
+	db := len(digits)
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+	if db-da > 1 {
+		return digits[(da+db+1)/2 : (da+db+1)/2+1]
+	}
+
+	// The first digits are consecutive.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return digits[da:da+1] + midpoint(tail(a, 1), "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+func tail(s string, n int) string {
+	if n >= len(s) {
+		return ""
+	}
+	return s[n:]
+}
+
+func incrementInteger(x string) (string, bool) {
+	head, digs := x[0], []byte(x[1:])
+	for i := len(digs) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, digs[i]) + 1
+		if d < len(digits) {
+			digs[i] = digits[d]
+			return string(head) + string(digs), true
+		}
+		digs[i] = digits[0]
+	}
+
+	// Every digit carried: move to the next, longer or shorter, length.
+	switch head {
+	case 'Z':
+		return "a" + digits[:1], true
+	case 'z':
+		return "", false
+	}
+	head++
+	if head > 'a' {
+		digs = append(digs, digits[0])
+	} else {
+		digs = digs[:len(digs)-1]
+	}
+	return string(head) + string(digs), true
+}
+
+func decrementInteger(x string) (string, bool) {
+	head, digs := x[0], []byte(x[1:])
+	last := digits[len(digits)-1]
+	for i := len(digs) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, digs[i]) - 1
+		if d >= 0 {
+			digs[i] = digits[d]
+			return string(head) + string(digs), true
+		}
+		digs[i] = last
+	}
+
+	switch head {
+	case 'a':
+		return "Z" + string(last), true
+	case 'A':
+		return "", false
+	}
+	head--
+	if head < 'Z' {
+		digs = append(digs, last)
+	} else {
+		digs = digs[:len(digs)-1]
+	}
+	return string(head) + string(digs), true
+}
@@ -0,0 +1,166 @@
+package rank
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// largestInteger is the highest integer part; nothing but fractions fit
+// after it.
+var largestInteger = "z" + strings.Repeat("z", 26)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"empty bounds", "", "", "a0"},
+		{"after a key", "a0", "", "a1"},
+		{"before a key", "", "a0", "Zz"},
+		{"before a key with a fraction", "", "a0V", "a0"},
+		{"after a key with a fraction", "a0V", "", "a1"},
+		{"adjacent integers", "a0", "a1", "a0V"},
+		{"adjacent fractions", "a0", "a0V", "a0G"},
+		{"consecutive digits", "a0G", "a0H", "a0GV"},
+		{"integer carries", "az", "", "b00"},
+		{"negative to positive", "Zz", "", "a0"},
+		{"past the largest integer", largestInteger, "", largestInteger + "V"},
+		{"before the smallest integer", "", smallestInteger + "1", smallestInteger + "0V"},
+		{"long fractions", "a0" + strings.Repeat("z", 40), "a1", "a0" + strings.Repeat("z", 40) + "V"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Between(%q, %q): %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+			checkBetween(t, tt.a, got, tt.b)
+		})
+	}
+}
+
+func TestBetweenErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want error
+	}{
+		{"bounds reversed", "a1", "a0", ErrNoRoom},
+		{"equal bounds", "a0V", "a0V", ErrNoRoom},
+		{"integer part too short", "a", "", ErrInvalidKey},
+		{"bad head", "!0", "", ErrInvalidKey},
+		{"bad digit", "a0-", "", ErrInvalidKey},
+		{"trailing zero", "a00", "", ErrInvalidKey},
+		{"smallest integer alone", smallestInteger, "", ErrInvalidKey},
+		{"bad upper bound", "", "a0 ", ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Between(%q, %q) = %q, %v, want %v", tt.a, tt.b, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetweenRepeatedInsertion(t *testing.T) {
+	// Always inserting next to the same bound only lengthens the fraction,
+	// by about one character every six insertions.
+	tests := []struct {
+		name       string
+		afterLower bool
+	}{
+		{"after the lower bound", true},
+		{"before the upper bound", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := "a0", "a1"
+			for i := 0; i < 600; i++ {
+				key, err := Between(a, b)
+				if err != nil {
+					t.Fatalf("insertion %d: %v", i, err)
+				}
+				checkBetween(t, a, key, b)
+				if tt.afterLower {
+					b = key
+				} else {
+					a = key
+				}
+			}
+			if n := max(len(a), len(b)); n > 2+600/5 {
+				t.Errorf("keys grew to %d characters", n)
+			}
+		})
+	}
+}
+
+func TestN(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		n    int
+	}{
+		{"none", "", "", 0},
+		{"one", "", "", 1},
+		{"unbounded", "", "", 1000},
+		{"after a key", "a5", "", 100},
+		{"before a key", "", "a5", 100},
+		{"between adjacent keys", "a0", "a1", 1000},
+		{"between long keys", "a0" + strings.Repeat("z", 30), "a0" + strings.Repeat("z", 30) + "1", 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := N(tt.a, tt.b, tt.n)
+			if err != nil {
+				t.Fatalf("N(%q, %q, %d): %v", tt.a, tt.b, tt.n, err)
+			}
+			if len(keys) != tt.n {
+				t.Fatalf("N(%q, %q, %d) returned %d keys", tt.a, tt.b, tt.n, len(keys))
+			}
+			prev := tt.a
+			for i, key := range keys {
+				if err := Validate(key); err != nil {
+					t.Fatalf("key %d: %v", i, err)
+				}
+				if prev != "" && key <= prev {
+					t.Fatalf("key %d = %q, not after %q", i, key, prev)
+				}
+				prev = key
+			}
+			if tt.b != "" && tt.n > 0 && prev >= tt.b {
+				t.Errorf("last key %q is not before %q", prev, tt.b)
+			}
+		})
+	}
+}
+
+func TestNErrors(t *testing.T) {
+	if _, err := N("a1", "a0", 3); !errors.Is(err, ErrNoRoom) {
+		t.Errorf("N with reversed bounds = %v, want ErrNoRoom", err)
+	}
+	if _, err := N("a", "", 3); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("N with a malformed bound = %v, want ErrInvalidKey", err)
+	}
+}
+
+// checkBetween fails unless key is a valid key strictly between a and b,
+// where empty bounds are open.
+func checkBetween(t *testing.T, a, key, b string) {
+	t.Helper()
+	if err := Validate(key); err != nil {
+		t.Fatal(err)
+	}
+	if a != "" && key <= a {
+		t.Fatalf("%q is not after %q", key, a)
+	}
+	if b != "" && key >= b {
+		t.Fatalf("%q is not before %q", key, b)
+	}
+}
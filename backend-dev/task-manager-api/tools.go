@@ -0,0 +1,9 @@
+//go:build tools
+
+// Package tools pins code generators run through `go run`, so their
+// versions are tracked in go.mod.
+package tools
+
+import (
+	_ "github.com/99designs/gqlgen"
+)